@@ -0,0 +1,239 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/libdns/libdns"
+)
+
+// ApplyFuncs holds the functions used by Apply to apply individual records.
+type ApplyFuncs struct {
+	Create func(ctx context.Context, record *libdns.RR) error
+	Delete func(ctx context.Context, record *libdns.RR) error
+	// Update is optional, when nil records marked as Update will be
+	// applied as a Delete of the previous and a Create of the new record.
+	Update func(ctx context.Context, previous, record *libdns.RR) error
+}
+
+type ApplyOptions struct {
+	// Workers is the amount of records that are applied concurrently,
+	// defaults to 1.
+	Workers int
+	// ContinueOnError will apply the next phase even when records of
+	// the current phase failed, by default it will stop after the
+	// phase that had failures.
+	ContinueOnError bool
+}
+
+// ErrNotApplied is reported for the records that Apply skipped, because an
+// earlier record failed or the context was cancelled.
+var ErrNotApplied = errors.New("not applied: earlier failure")
+
+type applyTask struct {
+	records []*libdns.RR
+	run     func(ctx context.Context) error
+	// ran is set when the task was run, so the other tasks can
+	// be reported as not applied
+	ran bool
+}
+
+// Apply applies the changes by calling the given functions for every record
+// and can be used by clients to implement SetDNSList. The records are applied
+// in the order of the change list (see ChangeOrder). For DeleteFirst and
+// CreateFirst the records are applied in phases (for example first the
+// deletes, then the updates and the creates) where the records of a phase
+// are applied concurrently by the configured amount of workers. For
+// Interleaved the sets are applied concurrently, where the records of a set
+// are applied one by one and will stop on the first failure. The sets that
+// only remove records are applied first and the sets that only add records
+// last, so a name is removed before it is (re)used by another set.
+//
+// The result of every record is reported on the change list, so the helpers
+// will return the applied records when some of them failed. The records that
+// were not run, because an earlier record of the set or phase failed (unless
+// ContinueOnError is set) or the context was cancelled, are reported with
+// ErrNotApplied. The returned error is a PartialError holding all failed and
+// skipped records.
+//
+// Example:
+//
+//	func (c *client) SetDNSList(ctx context.Context, domain string, change provider.ChangeList) ([]libdns.Record, error) {
+//		return nil, provider.Apply(ctx, change, provider.ApplyFuncs{
+//			Create: func(ctx context.Context, record *libdns.RR) error {
+//				return c.create(ctx, domain, record)
+//			},
+//			Delete: func(ctx context.Context, record *libdns.RR) error {
+//				return c.remove(ctx, domain, record)
+//			},
+//		}, &provider.ApplyOptions{Workers: 4})
+//	}
+func Apply(ctx context.Context, change ChangeList, funcs ApplyFuncs, options *ApplyOptions) error {
+
+	if nil == options {
+		options = new(ApplyOptions)
+	}
+
+	phases, err := applyPhases(change, funcs)
+
+	if err != nil {
+		return err
+	}
+
+	for _, groups := range phases {
+
+		var failed = applyPhase(ctx, change, groups, max(1, options.Workers))
+
+		if nil != ctx.Err() || (failed && false == options.ContinueOnError) {
+			break
+		}
+	}
+
+	for _, groups := range phases {
+		for _, group := range groups {
+			for _, task := range group {
+				if false == task.ran {
+					for _, record := range task.records {
+						change.Report(record, ErrNotApplied)
+					}
+				}
+			}
+		}
+	}
+
+	if err := change.failures(ctx.Err()); nil != err {
+		return err
+	}
+
+	return nil
+}
+
+// applyPhases returns the phases that should be applied one after another,
+// where every phase holds groups of tasks that can be applied concurrently.
+func applyPhases(change ChangeList, funcs ApplyFuncs) ([][][]*applyTask, error) {
+
+	var state = Delete | Create
+
+	if nil != funcs.Update {
+		state |= Update
+	}
+
+	var phases = make([][][]*applyTask, 0)
+	var weight = -1
+
+	for _, ordered := range change.ordered(state) {
+
+		var records = ordered.records
+		var group = make([]*applyTask, 0, len(records))
+
+		for _, record := range records {
+			task, err := newApplyTask(record, funcs)
+
+			if err != nil {
+				return nil, err
+			}
+
+			group = append(group, task)
+		}
+
+		// the sets are sorted by weight, where every weight is applied
+		// as a phase so the sets that only remove records go first
+		if change.Order() == Interleaved {
+			if ordered.weight != weight {
+				phases, weight = append(phases, make([][]*applyTask, 0)), ordered.weight
+			}
+			phases[len(phases)-1] = append(phases[len(phases)-1], group)
+			continue
+		}
+
+		// split the records in phases by state and
+		// apply every record on its own
+		for i, c := 0, len(records); i < c; i++ {
+			if i == 0 || records[i].state != records[i-1].state {
+				phases = append(phases, make([][]*applyTask, 0))
+			}
+			phases[len(phases)-1] = append(phases[len(phases)-1], group[i:i+1])
+		}
+	}
+
+	return phases, nil
+}
+
+func newApplyTask(record *ChangeRecord, funcs ApplyFuncs) (*applyTask, error) {
+
+	if record.state == Update {
+		return &applyTask{
+			records: []*libdns.RR{record.previous, record.record},
+			run: func(ctx context.Context) error {
+				return funcs.Update(ctx, record.previous, record.record)
+			},
+		}, nil
+	}
+
+	var fn = funcs.Create
+
+	if record.state == Delete {
+		fn = funcs.Delete
+	}
+
+	if nil == fn {
+		return nil, fmt.Errorf("no %s function provided to apply \"%s\"", record.state, FormatRecord(record.record))
+	}
+
+	return &applyTask{
+		records: []*libdns.RR{record.record},
+		run: func(ctx context.Context) error {
+			return fn(ctx, record.record)
+		},
+	}, nil
+}
+
+// applyPhase runs the groups with the given amount of workers, where the tasks
+// of a group are run one by one until one fails. The result of every record is
+// reported, and it returns true when one of the tasks failed.
+func applyPhase(ctx context.Context, change ChangeList, groups [][]*applyTask, workers int) bool {
+
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	var queue = make(chan []*applyTask)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				for _, task := range group {
+					var err = task.run(ctx)
+
+					task.ran = true
+
+					for _, record := range task.records {
+						change.Report(record, err)
+					}
+
+					if nil != err {
+						failed.Store(true)
+						break
+					}
+				}
+			}
+		}()
+	}
+
+loop:
+	for _, group := range groups {
+		select {
+		case <-ctx.Done():
+			break loop
+		case queue <- group:
+		}
+	}
+
+	close(queue)
+	wg.Wait()
+
+	return failed.Load()
+}
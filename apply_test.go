@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestApplyInterleavedRemovesFirst(t *testing.T) {
+
+	var change = NewChangeList(0, 4)
+
+	change.addRecord(libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1"}, Create)
+	change.addRecord(libdns.RR{Name: "b", Type: "CNAME", Data: "c.example.com."}, Delete)
+	change.addRecord(libdns.RR{Name: "c", Type: "TXT", Data: "x"}, Delete)
+	change.addRecord(libdns.RR{Name: "c", Type: "TXT", Data: "y"}, Create)
+	change.freeze()
+
+	var mutex sync.Mutex
+	var applied = make([]ChangeState, 0)
+	var record = func(state ChangeState, delay time.Duration) func(context.Context, *libdns.RR) error {
+		return func(context.Context, *libdns.RR) error {
+			time.Sleep(delay)
+			mutex.Lock()
+			defer mutex.Unlock()
+			applied = append(applied, state)
+			return nil
+		}
+	}
+
+	err := Apply(context.Background(), change.WithOrder(Interleaved), ApplyFuncs{
+		Create: record(Create, 0),
+		Delete: record(Delete, 10*time.Millisecond),
+	}, &ApplyOptions{Workers: 4})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if 4 != len(applied) || Delete != applied[0] || Create != applied[3] {
+		t.Fatalf("expected the delete only set to be applied first and the create only set last, got %v", applied)
+	}
+}
+
+func newApplyChange(order ChangeOrder, deletes, creates int) ChangeList {
+
+	var change = NewChangeList(0, deletes+creates)
+
+	for i := 0; i < deletes; i++ {
+		change.addRecord(libdns.RR{Name: fmt.Sprintf("d%d", i), Type: "TXT", Data: "x"}, Delete)
+	}
+
+	for i := 0; i < creates; i++ {
+		change.addRecord(libdns.RR{Name: fmt.Sprintf("c%d", i), Type: "TXT", Data: "x"}, Create)
+	}
+
+	change.freeze()
+
+	return change.WithOrder(order)
+}
+
+func TestApplyWorkers(t *testing.T) {
+
+	var mutex sync.Mutex
+	var running, peak int
+	var run = func(context.Context, *libdns.RR) error {
+		mutex.Lock()
+		running++
+		peak = max(peak, running)
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil
+	}
+
+	err := Apply(context.Background(), newApplyChange(DeleteFirst, 6, 0), ApplyFuncs{Create: run, Delete: run}, &ApplyOptions{Workers: 3})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if peak < 2 || peak > 3 {
+		t.Fatalf("expected at most 3 concurrent records, got %d", peak)
+	}
+}
+
+func TestApplyCancel(t *testing.T) {
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var change = newApplyChange(DeleteFirst, 1, 2)
+
+	err := Apply(ctx, change, ApplyFuncs{
+		Create: func(context.Context, *libdns.RR) error { return nil },
+		Delete: func(context.Context, *libdns.RR) error {
+			cancel()
+			return nil
+		},
+	}, nil)
+
+	var partial *PartialError
+
+	if false == errors.As(err, &partial) || false == errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a PartialError wrapping context.Canceled, got %v", err)
+	}
+
+	if 2 != len(partial.Errors) || false == errors.Is(partial.Errors[0], ErrNotApplied) || Create != partial.Errors[0].State {
+		t.Fatalf("expected the creates to be reported as not applied, got %v", partial.Errors)
+	}
+}
+
+func TestApplyContinueOnError(t *testing.T) {
+
+	for _, next := range []bool{false, true} {
+
+		var created atomic.Int32
+		var change = newApplyChange(DeleteFirst, 2, 2)
+
+		err := Apply(context.Background(), change, ApplyFuncs{
+			Create: func(context.Context, *libdns.RR) error {
+				created.Add(1)
+				return nil
+			},
+			Delete: func(_ context.Context, record *libdns.RR) error {
+				if "d0" == record.Name {
+					return errors.New("failed")
+				}
+				return nil
+			},
+		}, &ApplyOptions{ContinueOnError: next})
+
+		var partial *PartialError
+
+		if false == errors.As(err, &partial) {
+			t.Fatalf("expected a PartialError, got %v", err)
+		}
+
+		var skipped = 0
+
+		for _, x := range partial.Errors {
+			if errors.Is(x, ErrNotApplied) {
+				skipped++
+			}
+		}
+
+		if next && (2 != created.Load() || 1 != len(partial.Errors)) {
+			t.Fatalf("expected the creates to be applied, got %d created and %v", created.Load(), partial.Errors)
+		}
+
+		if false == next && (0 != created.Load() || 2 != skipped) {
+			t.Fatalf("expected the creates to be reported as not applied, got %d created and %v", created.Load(), partial.Errors)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/libdns/libdns"
+)
+
+// Batches will split the changes in ordered batches of at most size changed
+// records, so clients of APIs that limit the amount of changes per request
+// can loop over the batches instead of implementing their own chunking.
+//
+// All records of a set (same name and type) are kept in the same batch,
+// including the unchanged records, so a set that holds more than size
+// changes will be returned as a single batch that exceeds the size. Sets
+// that only remove records are returned first and sets that only create
+// records last. The batches have the same order as this list, so the
+// records of a batch can be iterated with IterateOrdered.
+//
+// Results reported on a batch are also reported on this list.
+//
+// A batch only holds a part of the zone, so it is marked as partial (see
+// IsPartial) and GetList should not be used to replace the whole zone with
+// the records of a batch.
+func (c *changes) Batches(size int) iter.Seq[ChangeList] {
+	return func(yield func(ChangeList) bool) {
+
+		var sets = slices.DeleteFunc(c.sets(), func(set *changeSet) bool {
+			return false == set.has(Delete|Create|Update)
+		})
+
+		slices.SortStableFunc(sets, func(a, b *changeSet) int {
+			return setWeight(a) - setWeight(b)
+		})
+
+		var batch = make([]*changeSet, 0)
+		var count = 0
+
+		for _, set := range sets {
+			var changed = set.count(Delete | Create | Update)
+
+			if len(batch) > 0 && size > 0 && count+changed > size {
+				if false == yield(newBatch(batch, c)) {
+					return
+				}
+				batch, count = make([]*changeSet, 0), 0
+			}
+
+			batch = append(batch, set)
+			count += changed
+		}
+
+		if len(batch) > 0 {
+			yield(newBatch(batch, c))
+		}
+	}
+}
+
+func newBatch(sets []*changeSet, parent *changes) ChangeList {
+
+	var change = &changes{
+		records: make([]*ChangeRecord, 0),
+		results: parent.results,
+		origins: make(map[*libdns.RR]libdns.Record),
+		order:   parent.order,
+		partial: true,
+	}
+
+	for _, state := range []ChangeState{Delete, Update, Create, NoChange} {
+		for _, set := range sets {
+			for _, record := range set.records {
+				if record.state == state {
+					change.add(record)
+				}
+			}
+		}
+	}
+
+	change.freeze()
+
+	return change
+}
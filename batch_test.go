@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestBatchesArePartial(t *testing.T) {
+
+	var change = NewChangeList(0, 3)
+
+	change.addRecord(libdns.RR{Name: "a", Type: "TXT", Data: "x"}, NoChange)
+	change.addRecord(libdns.RR{Name: "b", Type: "TXT", Data: "x"}, Create)
+	change.addRecord(libdns.RR{Name: "c", Type: "TXT", Data: "x"}, Delete)
+	change.freeze()
+
+	var client = NewZoneReplaceClient(&replaceClient{}, false)
+	var count = 0
+
+	for batch := range change.Batches(1) {
+		count++
+
+		if false == batch.IsPartial() {
+			t.Fatal("expected the batch to be partial")
+		}
+
+		if _, err := client.SetDNSList(context.Background(), "example.com.", batch); false == errors.Is(err, ErrPartialChangeList) {
+			t.Fatalf("expected ErrPartialChangeList, got %v", err)
+		}
+	}
+
+	if 2 != count {
+		t.Fatalf("expected 2 batches, got %d", count)
+	}
+}
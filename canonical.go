@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// CanonicalData returns the data of the record in a canonical form, so
+// records can be compared semantically instead of by the textual form
+// returned by an API:
+//
+//	A, AAAA       the IP address as formatted by netip
+//	TXT           the unquoted text, with multiple strings joined
+//	CNAME, NS     the target lowercased
+//	MX, SRV       the numbers without leading zeros and the target lowercased
+//	CAA           the flags, lowercase tag and quoted value
+//	SVCB, HTTPS   the params sorted by key and the target lowercased
+//
+// Targets are compared with A-labels and relative targets are kept relative,
+// because those are relative to the zone of the record. The helpers resolve
+// relative targets against the zone before comparing records.
+//
+// The data is returned as is for other types or when it cannot be parsed.
+func CanonicalData(record libdns.RR) string {
+
+	if "" == record.Data {
+		return ""
+	}
+
+	switch record.Type {
+	case "A", "AAAA":
+		if ip, err := netip.ParseAddr(record.Data); nil == err {
+			return ip.String()
+		}
+	case "TXT":
+		return canonicalText(record.Data)
+	case "CNAME", "NS":
+		return canonicalTarget(record.Data)
+	case "MX":
+		if fields := strings.Fields(record.Data); len(fields) == 2 {
+			if preference, err := strconv.ParseUint(fields[0], 10, 16); nil == err {
+				return fmt.Sprintf("%d %s", preference, canonicalTarget(fields[1]))
+			}
+		}
+	case "SRV":
+		return canonicalSRV(record.Data)
+	case "CAA":
+		if parsed, err := record.Parse(); nil == err {
+			var caa = parsed.(libdns.CAA)
+			return fmt.Sprintf("%d %s %q", caa.Flags, strings.ToLower(caa.Tag), caa.Value)
+		}
+	case "SVCB", "HTTPS":
+		return canonicalServiceBinding(record.Data)
+	}
+
+	return record.Data
+}
+
+// canonicalTarget returns the target lowercased with A-labels
+func canonicalTarget(target string) string {
+	return canonicalName(target)
+}
+
+// absoluteTarget returns the record with the target of CNAME, NS, MX, SRV,
+// SVCB and HTTPS records resolved against the zone when it is relative,
+// where targets are handled the same as names by relativeName.
+func absoluteTarget(record libdns.RR, zone string) libdns.RR {
+
+	var position int
+
+	switch record.Type {
+	case "CNAME", "NS":
+		position = 0
+	case "MX", "SVCB", "HTTPS":
+		position = 1
+	case "SRV":
+		position = 3
+	default:
+		return record
+	}
+
+	var origin = normalizeZoneKey(zone)
+	var rest = strings.TrimSpace(record.Data)
+	var fields = make([]string, 0, position+2)
+
+	if "" == origin {
+		return record
+	}
+
+	for i := 0; i < position; i++ {
+		field, tail, ok := strings.Cut(rest, " ")
+
+		if !ok {
+			return record
+		}
+
+		fields = append(fields, field)
+		rest = strings.TrimSpace(tail)
+	}
+
+	target, tail, _ := strings.Cut(rest, " ")
+
+	if "" == target {
+		return record
+	}
+
+	// like record names, a target that ends with the zone is a full name
+	// also without trailing dot (see relativeName)
+	if relative, err := relativeName(target, zone); nil == err {
+		target = libdns.AbsoluteName(relative, origin+".")
+	}
+
+	fields = append(fields, target)
+
+	if tail = strings.TrimSpace(tail); "" != tail {
+		fields = append(fields, tail)
+	}
+
+	record.Data = strings.Join(fields, " ")
+
+	return record
+}
+
+// canonicalText returns the text without quotes when the data is one
+// or more quoted strings, like `"foo" "bar"`, otherwise the data as is.
+func canonicalText(data string) string {
+
+	if len(data) < 2 || '"' != data[0] || '"' != data[len(data)-1] {
+		return data
+	}
+
+	var text strings.Builder
+	var quoted = false
+
+	for i, c := 0, len(data); i < c; i++ {
+		switch {
+		case '"' == data[i]:
+			quoted = !quoted
+		case '\\' == data[i] && quoted && i+1 < c:
+			i++
+
+			// \DDD is a decimal escaped byte
+			if i+2 < c && isDigits(data[i:i+3]) {
+				if x, err := strconv.ParseUint(data[i:i+3], 10, 8); nil == err {
+					text.WriteByte(byte(x))
+					i += 2
+					continue
+				}
+			}
+
+			text.WriteByte(data[i])
+		case quoted:
+			text.WriteByte(data[i])
+		case ' ' != data[i] && '\t' != data[i]:
+			// text outside the quotes, so not a list of strings
+			return data
+		}
+	}
+
+	if quoted {
+		return data
+	}
+
+	return text.String()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func canonicalSRV(data string) string {
+
+	var fields = strings.Fields(data)
+
+	if len(fields) != 4 {
+		return data
+	}
+
+	for i := 0; i < 3; i++ {
+		x, err := strconv.ParseUint(fields[i], 10, 16)
+
+		if err != nil {
+			return data
+		}
+
+		fields[i] = strconv.FormatUint(x, 10)
+	}
+
+	fields[3] = canonicalTarget(fields[3])
+
+	return strings.Join(fields, " ")
+}
+
+func canonicalServiceBinding(data string) string {
+
+	priority, rest, _ := strings.Cut(strings.TrimSpace(data), " ")
+	target, params, _ := strings.Cut(strings.TrimSpace(rest), " ")
+
+	x, err := strconv.ParseUint(priority, 10, 16)
+
+	if err != nil || "" == target {
+		return data
+	}
+
+	values, err := libdns.ParseSvcParams(params)
+
+	if err != nil {
+		return data
+	}
+
+	var keys = make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	var parts = []string{strconv.FormatUint(x, 10), canonicalTarget(target)}
+
+	for _, key := range keys {
+		parts = append(parts, libdns.SvcParams{strings.ToLower(key): values[key]}.String())
+	}
+
+	return strings.Join(parts, " ")
+}
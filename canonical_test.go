@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestCanonicalData(t *testing.T) {
+
+	var tests = []struct {
+		record   libdns.RR
+		expected string
+	}{
+		{libdns.RR{Type: "AAAA", Data: "2001:0db8:0000::0001"}, "2001:db8::1"},
+		{libdns.RR{Type: "A", Data: "192.0.2.1"}, "192.0.2.1"},
+		{libdns.RR{Type: "TXT", Data: `"foo" "bar\"\065"`}, `foobar"A`},
+		{libdns.RR{Type: "TXT", Data: `plain "text"`}, `plain "text"`},
+		{libdns.RR{Type: "MX", Data: "010 Mail.Example.com."}, "10 mail.example.com."},
+		{libdns.RR{Type: "MX", Data: "10 mail"}, "10 mail"},
+		{libdns.RR{Type: "SRV", Data: "0 05 443 Host.example.com."}, "0 5 443 host.example.com."},
+		{libdns.RR{Type: "CAA", Data: `0 Issue "letsencrypt.org"`}, `0 issue "letsencrypt.org"`},
+		{libdns.RR{Type: "HTTPS", Data: `1 . port=443 alpn=h2,h3`}, `1 . alpn=h2,h3 port=443`},
+		{libdns.RR{Type: "CNAME", Data: "Foo"}, "foo"},
+		{libdns.RR{Type: "CNAME", Data: "Foo.example.com."}, "foo.example.com."},
+		{libdns.RR{Type: "CNAME", Data: "bücher.example."}, "xn--bcher-kva.example."},
+	}
+
+	for _, test := range tests {
+		if x := CanonicalData(test.record); x != test.expected {
+			t.Errorf("CanonicalData(%s %q) = %q, expected %q", test.record.Type, test.record.Data, x, test.expected)
+		}
+	}
+}
+
+func TestRelativeTargets(t *testing.T) {
+
+	var matcher = zoneMatcher("example.com.", DefaultMatcher)
+
+	var tests = []struct {
+		a, b    libdns.RR
+		matcher Matcher
+		same    bool
+	}{
+		{libdns.RR{Name: "a", Type: "CNAME", Data: "foo"}, libdns.RR{Name: "a", Type: "CNAME", Data: "foo."}, DefaultMatcher, false},
+		{libdns.RR{Name: "a", Type: "CNAME", Data: "foo"}, libdns.RR{Name: "a", Type: "CNAME", Data: "foo."}, matcher, false},
+		{libdns.RR{Name: "a", Type: "CNAME", Data: "foo"}, libdns.RR{Name: "a", Type: "CNAME", Data: "foo.example.com."}, matcher, true},
+		{libdns.RR{Name: "a", Type: "CNAME", Data: "@"}, libdns.RR{Name: "a", Type: "CNAME", Data: "Example.com."}, matcher, true},
+		{libdns.RR{Name: "@", Type: "MX", Data: "10 mail"}, libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.com."}, matcher, true},
+		{libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.com"}, libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.com."}, matcher, true},
+		{libdns.RR{Name: "@", Type: "MX", Data: "10 Mail.Example.com"}, libdns.RR{Name: "@", Type: "MX", Data: "10 mail"}, matcher, true},
+		{libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.net"}, libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.net."}, matcher, false},
+		{libdns.RR{Name: "a", Type: "CNAME", Data: "b.example.com"}, libdns.RR{Name: "a", Type: "CNAME", Data: "b"}, matcher, true},
+		{libdns.RR{Name: "_sip._tcp", Type: "SRV", Data: "0 5 5060 sip"}, libdns.RR{Name: "_sip._tcp", Type: "SRV", Data: "0 5 5060 sip.example.com."}, matcher, true},
+		{libdns.RR{Name: "@", Type: "HTTPS", Data: "1 cdn alpn=h2"}, libdns.RR{Name: "@", Type: "HTTPS", Data: "1 cdn.example.com. alpn=h2"}, matcher, true},
+	}
+
+	for _, test := range tests {
+		if x := IsSameRecord(test.matcher, &test.a, &test.b, false); x != test.same {
+			t.Errorf("IsSameRecord(%q, %q) = %t, expected %t", test.a.Data, test.b.Data, x, test.same)
+		}
+	}
+}
+
+func TestPlanSetMatchesMXTarget(t *testing.T) {
+
+	var client = &memoryClient{
+		records: []libdns.Record{libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.com"}},
+	}
+
+	change, _, err := PlanSet(context.Background(), nil, client, "example.com.", []libdns.Record{
+		libdns.RR{Name: "@", Type: "MX", Data: "10 mail.example.com."},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if change.Has(Delete | Create | Update) {
+		t.Fatal("expected no changes for the same MX target with and without trailing dot")
+	}
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+var (
+	// ErrUnsupportedType is used when the client does not support the type of a record
+	ErrUnsupportedType = errors.New("unsupported record type")
+	// ErrTTLOutOfRange is used when the TTL of a record is not within the range of the client
+	ErrTTLOutOfRange = errors.New("ttl out of range")
+	// ErrTXTTooLong is used when a TXT record does not fit in a single string of 255 bytes
+	ErrTXTTooLong = errors.New("txt record exceeds 255 bytes")
+)
+
+type TTLPolicy uint8
+
+const (
+	// TTLClamp will change the TTL to the minimum or maximum TTL when out of range
+	TTLClamp TTLPolicy = iota
+	// TTLReject will return an error for records with a TTL out of range
+	TTLReject
+)
+
+// ClientCapabilities describes what a client supports, which is used by the
+// helpers to validate and normalize the input records before the changes are
+// computed, instead of failing deep inside the client.
+type ClientCapabilities struct {
+	// Types holds the supported record types, all types are supported when empty
+	Types []string
+	// MinTTL is the minimum TTL, no minimum when zero
+	MinTTL time.Duration
+	// MaxTTL is the maximum TTL, no maximum when zero
+	MaxTTL time.Duration
+	// TTLPolicy defines what to do with records that have a TTL out of range
+	TTLPolicy TTLPolicy
+	// MultipleTXTStrings should be true when the client supports TXT records
+	// that are longer than a single string of 255 bytes.
+	MultipleTXTStrings bool
+	// CaseSensitiveNames should be true when the client handles names case
+	// sensitive, otherwise names are lowercased.
+	CaseSensitiveNames bool
+	// UnicodeNames should be true when the client expects internationalized
+	// names as U-labels (like "bücher"), otherwise A-labels (like
+	// "xn--bcher-kva") are used.
+	UnicodeNames bool
+	// OmitZoneTrailingDot should be true when the client expects zone names
+	// without a trailing dot, like "example.com" instead of "example.com."
+	OmitZoneTrailingDot bool
+	// CaseSensitiveZones should be true when the client expects zone names
+	// as given by the caller, otherwise zone names are lowercased.
+	CaseSensitiveZones bool
+}
+
+// CapabilitiesAware can be implemented by a Client to let AppendRecords and
+// SetRecords validate and normalize the records before they are applied. An
+// invalid record will result in a RecordError that wraps ErrUnsupportedType,
+// ErrTTLOutOfRange or ErrTXTTooLong.
+type CapabilitiesAware interface {
+	Capabilities() *ClientCapabilities
+}
+
+// unicodeNames reports whether the client expects U-labels, see
+// ClientCapabilities.UnicodeNames.
+func unicodeNames(client any) bool {
+	v, ok := client.(CapabilitiesAware)
+	return ok && nil != v.Capabilities() && v.Capabilities().UnicodeNames
+}
+
+// caseSensitiveNames reports whether the client handles names case sensitive,
+// see ClientCapabilities.CaseSensitiveNames.
+func caseSensitiveNames(client any) bool {
+	v, ok := client.(CapabilitiesAware)
+	return ok && nil != v.Capabilities() && v.Capabilities().CaseSensitiveNames
+}
+
+// normalizeRecords makes the names relative to the zone and validates and
+// normalizes the records to the capabilities of the client, which is skipped
+// when the client does not implement CapabilitiesAware.
+func normalizeRecords(client Client, zone string, records []libdns.Record) ([]libdns.Record, error) {
+
+	records, err := normalizeNames(client, zone, records, Create)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := client.(CapabilitiesAware)
+
+	if !ok || nil == v.Capabilities() {
+		return records, nil
+	}
+
+	var capabilities = v.Capabilities()
+	var normalized = make([]libdns.Record, len(records))
+
+	for i, c := 0, len(records); i < c; i++ {
+		record, err := capabilities.normalize(records[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		normalized[i] = record
+	}
+
+	return normalized, nil
+}
+
+func (c *ClientCapabilities) normalize(record libdns.Record) (libdns.Record, error) {
+
+	var rr = record.RR()
+	var changed = false
+	var reject = func(err error) (libdns.Record, error) {
+		return nil, &RecordError{Record: &rr, State: Create, Err: err}
+	}
+
+	if len(c.Types) > 0 && false == slices.Contains(c.Types, rr.Type) {
+		return reject(ErrUnsupportedType)
+	}
+
+	if rr.Type == "TXT" && false == c.MultipleTXTStrings && len(rr.Data) > 255 {
+		return reject(ErrTXTTooLong)
+	}
+
+	// a zero TTL means the default TTL of the provider
+	if rr.TTL > 0 && ((c.MinTTL > 0 && rr.TTL < c.MinTTL) || (c.MaxTTL > 0 && rr.TTL > c.MaxTTL)) {
+
+		if c.TTLPolicy == TTLReject {
+			return reject(ErrTTLOutOfRange)
+		}
+
+		rr.TTL, changed = max(rr.TTL, c.MinTTL), true
+
+		if c.MaxTTL > 0 {
+			rr.TTL = min(rr.TTL, c.MaxTTL)
+		}
+	}
+
+	if false == c.CaseSensitiveNames && rr.Name != strings.ToLower(rr.Name) {
+		rr.Name, changed = strings.ToLower(rr.Name), true
+	}
+
+	if false == changed {
+		return record, nil
+	}
+
+	x, ok, err := withRR(record, rr)
+
+	if !ok {
+		return rr.Parse()
+	}
+
+	return x, err
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestNormalizeRecords(t *testing.T) {
+
+	var tests = []struct {
+		capabilities *ClientCapabilities
+		record       libdns.Record
+		ttl          time.Duration
+		err          error
+	}{
+		{&ClientCapabilities{MinTTL: time.Minute}, libdns.TXT{Name: "b", Text: "x"}, 0, nil},
+		{&ClientCapabilities{MinTTL: time.Minute, TTLPolicy: TTLReject}, libdns.TXT{Name: "b", Text: "x"}, 0, nil},
+		{&ClientCapabilities{MinTTL: time.Minute}, libdns.TXT{Name: "b", Text: "x", TTL: time.Second}, time.Minute, nil},
+		{&ClientCapabilities{MaxTTL: time.Hour}, libdns.TXT{Name: "b", Text: "x", TTL: 2 * time.Hour}, time.Hour, nil},
+		{&ClientCapabilities{MinTTL: time.Minute, TTLPolicy: TTLReject}, libdns.TXT{Name: "b", Text: "x", TTL: time.Second}, 0, ErrTTLOutOfRange},
+		{&ClientCapabilities{Types: []string{"A"}}, libdns.TXT{Name: "b", Text: "x"}, 0, ErrUnsupportedType},
+	}
+
+	for _, test := range tests {
+		records, err := normalizeRecords(&capabilitiesClient{capabilities: test.capabilities}, "example.com.", []libdns.Record{test.record})
+
+		if nil != test.err {
+			if false == errors.Is(err, test.err) {
+				t.Errorf("expected %v for %v, got %v", test.err, test.record, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if records[0].RR().TTL != test.ttl {
+			t.Errorf("expected ttl %s for %v, got %s", test.ttl, test.record, records[0].RR().TTL)
+		}
+	}
+}
+
+func TestNormalizeRecordsKeepsProviderData(t *testing.T) {
+
+	var client = &capabilitiesClient{capabilities: &ClientCapabilities{MinTTL: time.Minute}}
+
+	records, err := normalizeRecords(client, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "B", Text: "x", TTL: time.Second, ProviderData: "id-1"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, ok := records[0].(libdns.TXT)
+
+	if !ok || "id-1" != record.ProviderData || "b" != record.Name || time.Minute != record.TTL {
+		t.Fatalf("unexpected record %#v", records[0])
+	}
+}
+
+func TestAppendRecordsWithoutTTL(t *testing.T) {
+
+	var client = &capabilitiesClient{capabilities: &ClientCapabilities{MinTTL: time.Minute, TTLPolicy: TTLReject}}
+
+	if _, err := AppendRecords(context.Background(), nil, client, "example.com.", []libdns.Record{libdns.TXT{Name: "b", Text: "x"}}); err != nil {
+		t.Fatal(err)
+	}
+}
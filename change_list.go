@@ -1,7 +1,9 @@
 package provider
 
 import (
+	"fmt"
 	"iter"
+	"strings"
 
 	"github.com/libdns/libdns"
 )
@@ -12,11 +14,121 @@ const (
 	NoChange ChangeState = 1 << iota
 	Delete
 	Create
+	// Update marks a record that replaces an existing record with
+	// the same name and type, only differing in data or TTL. These
+	// records carry both the previous and the desired record.
+	Update
 )
 
+var changeStateNames = map[ChangeState]string{
+	NoChange: "none",
+	Delete:   "delete",
+	Create:   "create",
+	Update:   "update",
+}
+
+func (s ChangeState) String() string {
+	if name, ok := changeStateNames[s]; ok {
+		return name
+	}
+
+	var names = make([]string, 0)
+
+	for _, state := range []ChangeState{NoChange, Delete, Create, Update} {
+		if state == (s & state) {
+			names = append(names, changeStateNames[state])
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+func (s ChangeState) MarshalText() ([]byte, error) {
+	if _, ok := changeStateNames[s]; !ok {
+		return nil, fmt.Errorf("invalid change state %d", s)
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *ChangeState) UnmarshalText(text []byte) error {
+	for state, name := range changeStateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid change state \"%s\"", text)
+}
+
 type ChangeRecord struct {
-	record *libdns.RR
-	state  ChangeState
+	record   *libdns.RR
+	previous *libdns.RR
+	state    ChangeState
+	// origin and previousOrigin hold the records as returned
+	// by the client or given by the caller, so provider data
+	// like record ids is kept.
+	origin         libdns.Record
+	previousOrigin libdns.Record
+}
+
+// State returns the state this record is marked with
+func (c *ChangeRecord) State() ChangeState {
+	return c.state
+}
+
+// Record returns the record, for updates this is the desired record
+func (c *ChangeRecord) Record() *libdns.RR {
+	return c.record
+}
+
+// Previous returns the record that will be replaced when marked as
+// Update and nil for all other states
+func (c *ChangeRecord) Previous() *libdns.RR {
+	return c.previous
+}
+
+// Origin returns the record as it was returned by the client (for
+// existing records) or given by the caller (for new records), which
+// holds provider specific data like the ProviderData of the record.
+func (c *ChangeRecord) Origin() libdns.Record {
+	if nil == c.origin {
+		return *c.record
+	}
+	return c.origin
+}
+
+// PreviousOrigin returns the original record of Previous
+func (c *ChangeRecord) PreviousOrigin() libdns.Record {
+	return c.previousOrigin
+}
+
+// match yields the records of this change that are selected by the
+// given state. Updates are expanded to a Delete of the previous record
+// and a Create of the desired record, unless Update is part of the
+// given state, then they are left out so that they can be handled
+// with IterateUpdates.
+func (c *ChangeRecord) match(state ChangeState, yield func(ChangeState, *libdns.RR) bool) bool {
+
+	if c.state != Update {
+		if c.state == (c.state & state) {
+			return yield(c.state, c.record)
+		}
+		return true
+	}
+
+	if Update == (state & Update) {
+		return true
+	}
+
+	if Delete == (state&Delete) && false == yield(Delete, c.previous) {
+		return false
+	}
+
+	if Create == (state&Create) && false == yield(Create, c.record) {
+		return false
+	}
+
+	return true
 }
 
 type ChangeList interface {
@@ -27,7 +139,55 @@ type ChangeList interface {
 	// multiple states like `Iterate(Delete|Create)` which
 	// will return all records that are marked delete or
 	// as created.
+	//
+	// Records marked as Update are returned as a Delete of the
+	// previous record and a Create of the new record, so clients
+	// that do not support updates keep working. Clients that do
+	// support updates can include Update in the state (for example
+	// `Iterate(Delete|Update)`) to exclude those records and handle
+	// them with IterateUpdates instead.
 	Iterate(state ChangeState) iter.Seq[*libdns.RR]
+	// IterateStates works the same as Iterate but will also
+	// return the state of every record, so it is possible to
+	// handle all changes in a single loop.
+	IterateStates(state ChangeState) iter.Seq2[ChangeState, *libdns.RR]
+	// IterateOrigins works the same as Iterate but will also
+	// return the original record, as returned by the client
+	// or given by the caller. This can be used to access the
+	// provider specific data of records, like record ids.
+	IterateOrigins(state ChangeState) iter.Seq2[*libdns.RR, libdns.Record]
+	// Origin returns the original record of a record returned
+	// by this list, see IterateOrigins.
+	Origin(record *libdns.RR) libdns.Record
+	// IterateUpdates will return an iterator that returns the
+	// previous and the new record of all records marked as Update
+	IterateUpdates() iter.Seq2[*libdns.RR, *libdns.RR]
+	// RRSets will return an iterator that returns the changed
+	// records grouped by name and type, with the records of the
+	// set before and after the change. Sets without changes are
+	// not returned.
+	RRSets() iter.Seq[*RRSet]
+	// Order returns the order in which the changes should
+	// be applied.
+	Order() ChangeOrder
+	// WithOrder returns a frozen copy of this list with the
+	// given order.
+	WithOrder(order ChangeOrder) ChangeList
+	// IterateOrdered will return an iterator that returns the
+	// records that match the given state in the order of this
+	// list. Updates are expanded the same as with Iterate,
+	// unless Update is part of the state, then they will be
+	// returned as a single record with the Update state.
+	IterateOrdered(state ChangeState) iter.Seq[*ChangeRecord]
+	// Batches will return an iterator that splits the
+	// changes in batches of at most size changed records,
+	// keeping records with the same name and type together
+	// and returning removals before creations.
+	Batches(size int) iter.Seq[ChangeList]
+	// Invert will return a new list that reverts the changes
+	// of this list, records marked for deletion are marked
+	// for creation and the other way around.
+	Invert() ChangeList
 	// Creates will return a slice of records that are
 	// marked for creating
 	Creates() []*libdns.RR
@@ -39,16 +199,61 @@ type ChangeList interface {
 	// to update the whole set for a zone
 	GetList() []*libdns.RR
 	// Has wil check if this list has records for
-	// given state
+	// given state. Because updates are also presented
+	// as delete and create, a list with updates will
+	// also report Delete and Create.
 	Has(state ChangeState) bool
+	// Report can be used by the client to report the result
+	// of a record returned by this list, where a nil error
+	// marks the record as applied. When the results are
+	// reported, the helpers will return the applied records
+	// together with a PartialError describing the failures
+	// instead of failing the whole operation.
+	Report(record *libdns.RR, err error)
+	// Results will return an iterator that returns all
+	// records with their reported result.
+	Results() iter.Seq2[*libdns.RR, error]
+	// IsPartial reports whether the list only holds the
+	// records of a part of the zone, which is the case when
+	// the records were fetched with a FilteredClient. The
+	// GetList of a partial list should not be used to
+	// replace the whole zone.
+	IsPartial() bool
+	// IsFrozen reports whether the list is frozen. A
+	// frozen list will panic when records are added,
+	// all lists are frozen before they are passed to
+	// the Client.
+	IsFrozen() bool
 	// addRecord is not exported because the record
 	// list is immutable
-	addRecord(record *libdns.RR, state ChangeState)
+	addRecord(record libdns.Record, state ChangeState)
+	// addUpdate is not exported because the record
+	// list is immutable
+	addUpdate(previous, record libdns.Record)
+	// freeze will make the list read only
+	freeze()
+	// markPartial marks the list as partial, which
+	// should be done before the list is frozen
+	markPartial()
+	// isReported reports whether the client reported results
+	isReported() bool
+	// failures returns the reported failures
+	failures(cause error) *PartialError
+	// applied returns the records reported as applied
+	applied(state ChangeState) ([]libdns.Record, error)
+	// ordered returns the records grouped in the order of the list
+	ordered(state ChangeState) []*changeGroup
 }
 
 type changes struct {
 	records []*ChangeRecord
 	state   ChangeState
+	frozen  bool
+	results *changeResults
+	order   ChangeOrder
+	origins map[*libdns.RR]libdns.Record
+	partial bool
+	free    int
 }
 
 func NewChangeList(size ...int) ChangeList {
@@ -66,31 +271,85 @@ func NewChangeList(size ...int) ChangeList {
 
 	return &changes{
 		records: records,
+		results: newChangeResults(),
+		origins: make(map[*libdns.RR]libdns.Record),
 	}
 }
 
-func (c *changes) addRecord(record *libdns.RR, state ChangeState) {
-	var idx *int
+func (c *changes) addRecord(record libdns.Record, state ChangeState) {
+	var rr = record.RR()
 
-	for i, record := range c.records {
-		if record == nil {
-			idx = &i
-			break
-		}
+	c.add(&ChangeRecord{
+		record: &rr,
+		state:  state,
+		origin: record,
+	})
+}
+
+func (c *changes) addUpdate(previous, record libdns.Record) {
+	var a, b = previous.RR(), record.RR()
+
+	c.add(&ChangeRecord{
+		record:         &b,
+		previous:       &a,
+		state:          Update,
+		origin:         record,
+		previousOrigin: previous,
+	})
+}
+
+func (c *changes) add(change *ChangeRecord) {
+
+	if c.frozen {
+		panic("provider: cannot add records to a frozen change list")
 	}
 
-	var change = &ChangeRecord{
-		record: record,
-		state:  state,
+	// records are never removed, so the empty slots can
+	// only be found after the last filled slot
+	for c.free < len(c.records) && nil != c.records[c.free] {
+		c.free++
 	}
 
-	if idx == nil {
+	if c.free == len(c.records) {
 		c.records = append(c.records, change)
 	} else {
-		c.records[*idx] = change
+		c.records[c.free] = change
 	}
 
-	c.state |= state
+	c.free++
+
+	c.origins[change.record] = change.origin
+
+	if nil != change.previous {
+		c.origins[change.previous] = change.previousOrigin
+	}
+
+	if change.state == Update {
+		c.state |= Update | Delete | Create
+	} else {
+		c.state |= change.state
+	}
+}
+
+func (c *changes) freeze() {
+	c.frozen = true
+}
+
+func (c *changes) IsFrozen() bool {
+	return c.frozen
+}
+
+func (c *changes) markPartial() {
+
+	if c.frozen {
+		panic("provider: cannot mark a frozen change list as partial")
+	}
+
+	c.partial = true
+}
+
+func (c *changes) IsPartial() bool {
+	return c.partial
 }
 
 func (c *changes) Has(state ChangeState) bool {
@@ -99,9 +358,46 @@ func (c *changes) Has(state ChangeState) bool {
 
 func (c *changes) Iterate(state ChangeState) iter.Seq[*libdns.RR] {
 	return func(yield func(*libdns.RR) bool) {
+		for _, record := range c.IterateStates(state) {
+			if false == yield(record) {
+				return
+			}
+		}
+	}
+}
+
+func (c *changes) IterateStates(state ChangeState) iter.Seq2[ChangeState, *libdns.RR] {
+	return func(yield func(ChangeState, *libdns.RR) bool) {
 		for i, x := 0, len(c.records); i < x; i++ {
-			if nil != c.records[i] && c.records[i].state == (c.records[i].state&state) {
-				if false == yield(c.records[i].record) {
+			if nil != c.records[i] && false == c.records[i].match(state, yield) {
+				return
+			}
+		}
+	}
+}
+
+func (c *changes) IterateOrigins(state ChangeState) iter.Seq2[*libdns.RR, libdns.Record] {
+	return func(yield func(*libdns.RR, libdns.Record) bool) {
+		for _, record := range c.IterateStates(state) {
+			if false == yield(record, c.Origin(record)) {
+				return
+			}
+		}
+	}
+}
+
+func (c *changes) Origin(record *libdns.RR) libdns.Record {
+	if origin, ok := c.origins[record]; ok && nil != origin {
+		return origin
+	}
+	return *record
+}
+
+func (c *changes) IterateUpdates() iter.Seq2[*libdns.RR, *libdns.RR] {
+	return func(yield func(*libdns.RR, *libdns.RR) bool) {
+		for i, x := 0, len(c.records); i < x; i++ {
+			if nil != c.records[i] && c.records[i].state == Update {
+				if false == yield(c.records[i].previous, c.records[i].record) {
 					return
 				}
 			}
@@ -109,6 +405,39 @@ func (c *changes) Iterate(state ChangeState) iter.Seq[*libdns.RR] {
 	}
 }
 
+func (c *changes) Invert() ChangeList {
+
+	var inverse = &changes{
+		records: make([]*ChangeRecord, 0, len(c.records)),
+		results: newChangeResults(),
+		order:   c.order,
+		origins: make(map[*libdns.RR]libdns.Record),
+		partial: c.partial,
+	}
+
+	for _, record := range c.records {
+
+		if nil == record {
+			continue
+		}
+
+		switch record.state {
+		case Delete:
+			inverse.addRecord(record.origin, Create)
+		case Create:
+			inverse.addRecord(record.origin, Delete)
+		case Update:
+			inverse.addUpdate(record.origin, record.previousOrigin)
+		default:
+			inverse.addRecord(record.origin, record.state)
+		}
+	}
+
+	inverse.freeze()
+
+	return inverse
+}
+
 func (c *changes) Creates() []*libdns.RR {
 	return c.list(Create)
 }
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"github.com/libdns/libdns"
+)
+
+// ChangeListBuilder can be used to create a ChangeList outside of this
+// package, which is mostly useful to test a Client implementation.
+//
+// Example:
+//
+//	change := provider.NewChangeListBuilder().
+//		Add(provider.NoChange, libdns.TXT{Name: "foo", Text: "bar"}).
+//		Add(provider.Delete, libdns.TXT{Name: "foo", Text: "baz"}).
+//		Update(libdns.TXT{Name: "www", Text: "old"}, libdns.TXT{Name: "www", Text: "new"}).
+//		Build()
+//
+//	records, err := client.SetDNSList(ctx, "example.com.", change)
+type ChangeListBuilder struct {
+	list  ChangeList
+	order ChangeOrder
+}
+
+func NewChangeListBuilder() *ChangeListBuilder {
+	return &ChangeListBuilder{
+		list: NewChangeList(),
+	}
+}
+
+// Add appends the given records with the given state. Records that should
+// be marked as Update need a previous record and should be added with Update.
+func (b *ChangeListBuilder) Add(state ChangeState, records ...libdns.Record) *ChangeListBuilder {
+
+	if state == Update {
+		panic("provider: use Update to add records marked as update")
+	}
+
+	for _, record := range records {
+		b.list.addRecord(record, state)
+	}
+	return b
+}
+
+// Update appends a record that replaces the previous record
+func (b *ChangeListBuilder) Update(previous, record libdns.Record) *ChangeListBuilder {
+	b.list.addUpdate(previous, record)
+	return b
+}
+
+// Order sets the order of the list, see ChangeOrder
+func (b *ChangeListBuilder) Order(order ChangeOrder) *ChangeListBuilder {
+	b.order = order
+	return b
+}
+
+// Build returns the frozen ChangeList, any call to Add or Update after
+// this will panic.
+func (b *ChangeListBuilder) Build() ChangeList {
+	b.list.freeze()
+	return b.list.WithOrder(b.order)
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func collect(seq iter.Seq[*libdns.RR]) []libdns.RR {
+
+	var records = make([]libdns.RR, 0)
+
+	for record := range seq {
+		records = append(records, *record)
+	}
+
+	return records
+}
+
+func TestChangeListUpdate(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		previous libdns.RR
+		record   libdns.RR
+	}{
+		{"data", libdns.RR{Name: "a", Type: "TXT", Data: "x"}, libdns.RR{Name: "a", Type: "TXT", Data: "y"}},
+		{"ttl", libdns.RR{Name: "a", Type: "TXT", TTL: time.Hour, Data: "x"}, libdns.RR{Name: "a", Type: "TXT", TTL: time.Minute, Data: "x"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var existing = []libdns.Record{test.previous, libdns.RR{Name: "b", Type: "TXT", Data: "x"}}
+
+			change, _, err := planSet(sliceStream(existing), []libdns.Record{test.record}, DefaultMatcher, false)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if false == change.Has(Update) {
+				t.Fatal("expected the change to be paired into an update")
+			}
+
+			if x := collect(change.Iterate(Delete)); false == slices.Equal(x, []libdns.RR{test.previous}) {
+				t.Fatalf("expected Iterate(Delete) to return the previous record, got %v", x)
+			}
+
+			if x := collect(change.Iterate(Create)); false == slices.Equal(x, []libdns.RR{test.record}) {
+				t.Fatalf("expected Iterate(Create) to return the new record, got %v", x)
+			}
+
+			if x := collect(change.Iterate(Delete | Create | Update)); 0 != len(x) {
+				t.Fatalf("expected Iterate(Delete|Create|Update) to leave out the update, got %v", x)
+			}
+
+			for previous, record := range change.IterateUpdates() {
+				if *previous != test.previous || *record != test.record {
+					t.Fatalf("unexpected update %v -> %v", previous, record)
+				}
+			}
+
+			var list = make([]libdns.RR, 0)
+
+			for _, record := range change.GetList() {
+				list = append(list, *record)
+			}
+
+			if false == slices.Contains(list, test.record) || slices.Contains(list, test.previous) || 2 != len(list) {
+				t.Fatalf("expected GetList to hold the new record, got %v", list)
+			}
+		})
+	}
+}
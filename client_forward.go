@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/libdns/libdns"
+)
+
+type filterer interface {
+	GetDNSListFiltered(ctx context.Context, domain string, names, types []string) ([]libdns.Record, error)
+}
+
+// forwarder forwards the optional interfaces (like RollbackAware or
+// StreamingClient) to the client wrapped by an adapter. The methods are
+// always available on the adapter, so when the wrapped client does not
+// implement an interface they fall back to the default behaviour.
+type forwarder struct {
+	client interface {
+		GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error)
+	}
+}
+
+// Domains returns the domains of the wrapped client, or an error wrapping
+// errors.ErrUnsupported when the client cannot list domains.
+func (f *forwarder) Domains(ctx context.Context) ([]Domain, error) {
+	if x, ok := f.client.(interface {
+		Domains(ctx context.Context) ([]Domain, error)
+	}); ok {
+		return x.Domains(ctx)
+	}
+	return nil, fmt.Errorf("%T cannot list domains: %w", f.client, errors.ErrUnsupported)
+}
+
+// IterateDNSList uses the iterator of the wrapped client, or GetDNSList
+// when the client does not support streaming.
+func (f *forwarder) IterateDNSList(ctx context.Context, domain string) iter.Seq2[libdns.Record, error] {
+
+	if x, ok := f.client.(interface {
+		IterateDNSList(ctx context.Context, domain string) iter.Seq2[libdns.Record, error]
+	}); ok {
+		return x.IterateDNSList(ctx, domain)
+	}
+
+	return func(yield func(libdns.Record, error) bool) {
+		list, err := f.client.GetDNSList(ctx, domain)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for record, err := range sliceStream(list) {
+			if false == yield(record, err) {
+				return
+			}
+		}
+	}
+}
+
+// GetDNSListFiltered uses the filter of the wrapped client, or GetDNSList
+// when the client does not support filtering.
+func (f *forwarder) GetDNSListFiltered(ctx context.Context, domain string, names, types []string) ([]libdns.Record, error) {
+	if x, ok := f.client.(filterer); ok {
+		return x.GetDNSListFiltered(ctx, domain, names, types)
+	}
+	return f.client.GetDNSList(ctx, domain)
+}
+
+// supportsFilter reports whether the wrapped client supports filtering,
+// see newRecordFilter.
+func (f *forwarder) supportsFilter() bool {
+	_, ok := f.client.(filterer)
+	return ok
+}
+
+func (f *forwarder) RollbackOnFailure() bool {
+	v, ok := f.client.(RollbackAware)
+	return ok && v.RollbackOnFailure()
+}
+
+func (f *forwarder) Capabilities() *ClientCapabilities {
+	if x, ok := f.client.(CapabilitiesAware); ok {
+		return x.Capabilities()
+	}
+	return nil
+}
+
+func (f *forwarder) Matcher() Matcher {
+	if x, ok := f.client.(MatcherAware); ok {
+		return x.Matcher()
+	}
+	return nil
+}
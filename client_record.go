@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/libdns/libdns"
+)
+
+// RecordClient is a simpler client for APIs that manage records one by one
+// (for example POST to create and DELETE by id), which can be turned into
+// a Client with NewRecordClient.
+type RecordClient interface {
+	// GetDNSList returns all DNS records available for the given zone,
+	// see Client.GetDNSList.
+	GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error)
+	// CreateRecord creates a single record in the given zone
+	CreateRecord(ctx context.Context, domain string, record libdns.Record) error
+	// DeleteRecord removes a single record from the given zone, the record
+	// is the one returned by GetDNSList so it holds the provider data
+	DeleteRecord(ctx context.Context, domain string, record libdns.Record) error
+}
+
+// RecordUpdater can be implemented by a RecordClient that supports updating
+// a record in place, otherwise updates are applied as a delete and create.
+type RecordUpdater interface {
+	UpdateRecord(ctx context.Context, domain string, previous, record libdns.Record) error
+}
+
+type recordClient struct {
+	forwarder
+	client  RecordClient
+	options *ApplyOptions
+}
+
+// NewRecordClient returns a Client that applies the changes with the given
+// RecordClient, using Apply with the given options. The order in which the
+// records are applied follows the order of the ChangeList.
+//
+// The optional interfaces implemented by the given client (like Domains,
+// StreamingClient, FilteredClient or RollbackAware) are forwarded by the
+// returned Client, so it can also be used as a ZoneAwareClient.
+func NewRecordClient(client RecordClient, options *ApplyOptions) Client {
+	return &recordClient{
+		forwarder: forwarder{client: client},
+		client:    client,
+		options:   options,
+	}
+}
+
+func (r *recordClient) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+	return r.client.GetDNSList(ctx, domain)
+}
+
+func (r *recordClient) SetDNSList(ctx context.Context, domain string, change ChangeList) ([]libdns.Record, error) {
+
+	var funcs = ApplyFuncs{
+		Create: func(ctx context.Context, record *libdns.RR) error {
+			return r.client.CreateRecord(ctx, domain, change.Origin(record))
+		},
+		Delete: func(ctx context.Context, record *libdns.RR) error {
+			return r.client.DeleteRecord(ctx, domain, change.Origin(record))
+		},
+	}
+
+	if v, ok := r.client.(RecordUpdater); ok {
+		funcs.Update = func(ctx context.Context, previous, record *libdns.RR) error {
+			return v.UpdateRecord(ctx, domain, change.Origin(previous), change.Origin(record))
+		}
+	}
+
+	return nil, Apply(ctx, change, funcs, r.options)
+}
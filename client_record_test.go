@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+// domainRecordClient is a RecordClient that can also list its domains
+type domainRecordClient struct {
+	idRecordClient
+	domains []string
+}
+
+func (c *domainRecordClient) Domains(ctx context.Context) ([]Domain, error) {
+
+	var domains = make([]Domain, len(c.domains))
+
+	for i, name := range c.domains {
+		domains[i] = domain(name)
+	}
+
+	return domains, nil
+}
+
+func TestRecordClientForwardsDomains(t *testing.T) {
+
+	var client = NewRecordClient(&domainRecordClient{domains: []string{"example.com", "b.example.com"}}, nil)
+
+	aware, ok := client.(ZoneAwareClient)
+
+	if !ok {
+		t.Fatal("expected the adapter to be a ZoneAwareClient")
+	}
+
+	zone, name, err := FindZone(context.Background(), aware, "_acme-challenge.a.b.example.com.")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if zone.Name != "b.example.com." || name != "_acme-challenge.a" {
+		t.Fatalf("unexpected zone \"%s\" and name \"%s\"", zone.Name, name)
+	}
+
+	if _, err := NewRecordClient(&idRecordClient{}, nil).(ZoneAwareClient).Domains(context.Background()); false == errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected errors.ErrUnsupported, got %v", err)
+	}
+}
+
+func TestRecordClientStreamsRecords(t *testing.T) {
+
+	var client = NewRecordClient(&idRecordClient{
+		records: []libdns.Record{
+			libdns.TXT{Name: "a", Text: "x", ProviderData: "id-1"},
+			libdns.TXT{Name: "b", Text: "x", ProviderData: "id-2"},
+		},
+	}, nil)
+
+	stream, ok := client.(StreamingClient)
+
+	if !ok {
+		t.Fatal("expected the adapter to be a StreamingClient")
+	}
+
+	var n int
+
+	for _, err := range stream.IterateDNSList(context.Background(), "example.com.") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+
+	if n != 2 {
+		t.Fatalf("expected 2 records, got %d", n)
+	}
+
+	if nil != newRecordFilter(client, []libdns.Record{libdns.RR{Name: "a", Type: "TXT"}}) {
+		t.Fatal("expected no filter for a client that does not support filtering")
+	}
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/libdns/libdns"
+)
+
+// memoryClient is a Client that keeps the records in memory, where creating
+// records with a name listed in fail will return an error.
+type memoryClient struct {
+	records  []libdns.Record
+	fail     []string
+	rollback bool
+}
+
+func (m *memoryClient) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+	return slices.Clone(m.records), nil
+}
+
+func (m *memoryClient) SetDNSList(ctx context.Context, domain string, change ChangeList) ([]libdns.Record, error) {
+
+	for record := range change.Iterate(Delete) {
+		if i := slices.IndexFunc(m.records, func(x libdns.Record) bool { return x.RR() == *record }); i >= 0 {
+			m.records = slices.Delete(m.records, i, i+1)
+		}
+	}
+
+	for record := range change.Iterate(Create) {
+
+		if slices.Contains(m.fail, record.Name) {
+			return nil, fmt.Errorf("failed to create %s", record.Name)
+		}
+
+		m.records = append(m.records, *record)
+	}
+
+	return nil, nil
+}
+
+func (m *memoryClient) RollbackOnFailure() bool {
+	return m.rollback
+}
+
+type domain string
+
+func (d domain) Name() string {
+	return string(d)
+}
+
+// zoneClient is a ZoneAwareClient with the given domains
+type zoneClient struct {
+	memoryClient
+	domains []string
+	unicode bool
+}
+
+func (z *zoneClient) Domains(ctx context.Context) ([]Domain, error) {
+
+	var domains = make([]Domain, len(z.domains))
+
+	for i, name := range z.domains {
+		domains[i] = domain(name)
+	}
+
+	return domains, nil
+}
+
+func (z *zoneClient) Capabilities() *ClientCapabilities {
+	return &ClientCapabilities{UnicodeNames: z.unicode}
+}
+
+// capabilitiesClient is a memoryClient with the given capabilities
+type capabilitiesClient struct {
+	memoryClient
+	capabilities *ClientCapabilities
+}
+
+func (c *capabilitiesClient) Capabilities() *ClientCapabilities {
+	return c.capabilities
+}
+
+// replaceClient is a ZoneReplaceClient that counts the calls to GetDNSList
+type replaceClient struct {
+	records []libdns.Record
+	gets    int
+}
+
+func (r *replaceClient) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+	r.gets++
+	return slices.Clone(r.records), nil
+}
+
+func (r *replaceClient) ReplaceZone(ctx context.Context, domain string, records []libdns.Record) ([]libdns.Record, error) {
+	r.records = slices.Clone(records)
+	return slices.Clone(records), nil
+}
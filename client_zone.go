@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrEmptyZone is returned when a zone would be replaced with an empty
+// record list, which is refused unless explicitly allowed.
+var ErrEmptyZone = errors.New("refusing to replace zone with an empty record list")
+
+// ZoneReplaceClient is a client for APIs that can only replace the whole
+// zone at once, which can be turned into a Client with NewZoneReplaceClient.
+type ZoneReplaceClient interface {
+	// GetDNSList returns all DNS records available for the given zone,
+	// see Client.GetDNSList.
+	GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error)
+	// ReplaceZone replaces all records of the zone with the given records
+	// and returns the new record set, or nil when the API does not return
+	// the records.
+	ReplaceZone(ctx context.Context, domain string, records []libdns.Record) ([]libdns.Record, error)
+}
+
+type zoneReplaceClient struct {
+	forwarder
+	client     ZoneReplaceClient
+	allowEmpty bool
+}
+
+// NewZoneReplaceClient returns a Client that passes the new record list of
+// the ChangeList (see ChangeList.GetList) to the given ZoneReplaceClient. To
+// protect against wiping a zone, it will return ErrEmptyZone when the list
+// is empty unless allowEmpty is true.
+//
+// The optional interfaces implemented by the given client (like Domains,
+// StreamingClient or RollbackAware) are forwarded by the returned Client,
+// except FilteredClient because the whole zone is needed to replace it.
+func NewZoneReplaceClient(client ZoneReplaceClient, allowEmpty bool) Client {
+	return &zoneReplaceClient{
+		forwarder:  forwarder{client: client},
+		client:     client,
+		allowEmpty: allowEmpty,
+	}
+}
+
+func (z *zoneReplaceClient) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+	return z.client.GetDNSList(ctx, domain)
+}
+
+// supportsFilter disables the filter, because the records of the whole
+// zone are needed to replace the zone.
+func (z *zoneReplaceClient) supportsFilter() bool {
+	return false
+}
+
+func (z *zoneReplaceClient) SetDNSList(ctx context.Context, domain string, change ChangeList) ([]libdns.Record, error) {
+
+	if change.IsPartial() {
+		return nil, ErrPartialChangeList
+	}
+
+	var records = make([]libdns.Record, 0)
+
+	for _, record := range change.IterateOrigins(Create | NoChange) {
+		records = append(records, record)
+	}
+
+	if len(records) == 0 && false == z.allowEmpty {
+		return nil, ErrEmptyZone
+	}
+
+	return z.client.ReplaceZone(ctx, domain, records)
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestZoneReplaceClientEmptyZone(t *testing.T) {
+
+	for _, allow := range []bool{false, true} {
+
+		var records = &replaceClient{records: []libdns.Record{libdns.RR{Name: "a", Type: "TXT", Data: "x"}}}
+		var client = NewZoneReplaceClient(records, allow)
+
+		removed, err := DeleteRecords(context.Background(), nil, client, "example.com.", []libdns.Record{
+			libdns.RR{Name: "a", Type: "TXT"},
+		})
+
+		if false == allow && (false == errors.Is(err, ErrEmptyZone) || 1 != len(records.records)) {
+			t.Fatalf("expected ErrEmptyZone and the zone to be kept, got %v", err)
+		}
+
+		if allow && (err != nil || 1 != len(removed) || 0 != len(records.records)) {
+			t.Fatalf("expected the zone to be emptied, got %v (%v)", removed, err)
+		}
+	}
+}
+
+func TestZoneReplaceClientPartial(t *testing.T) {
+
+	var records = &replaceClient{records: []libdns.Record{libdns.RR{Name: "a", Type: "TXT", Data: "x"}}}
+
+	change, _, err := planSet(sliceStream(records.records), []libdns.Record{libdns.RR{Name: "b", Type: "TXT", Data: "x"}}, DefaultMatcher, true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewZoneReplaceClient(records, false).SetDNSList(context.Background(), "example.com.", change); false == errors.Is(err, ErrPartialChangeList) {
+		t.Fatalf("expected ErrPartialChangeList, got %v", err)
+	}
+
+	if 1 != len(records.records) {
+		t.Fatalf("expected the zone to be kept, got %v", records.records)
+	}
+}
+
+func TestZoneReplaceClientReturnsRecords(t *testing.T) {
+
+	var records = &replaceClient{records: []libdns.Record{libdns.RR{Name: "a", Type: "TXT", Data: "x"}}}
+
+	created, err := SetRecords(context.Background(), nil, NewZoneReplaceClient(records, false), "example.com.", []libdns.Record{
+		libdns.RR{Name: "b", Type: "TXT", Data: "x"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if 1 != len(created) || "b" != created[0].RR().Name || 2 != len(records.records) {
+		t.Fatalf("unexpected records %v, zone holds %v", created, records.records)
+	}
+
+	if 1 != records.gets {
+		t.Fatalf("expected the returned records to be used instead of GetDNSList, got %d calls", records.gets)
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const (
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorReset = "\033[0m"
+)
+
+// DiffWriter renders a ChangeList as a zone file like diff, where records
+// are grouped by name and type and removed records are prefixed with "-"
+// and created records with "+". Records marked as Update are printed as
+// a removal of the previous and a creation of the new record.
+//
+// Example output:
+//
+//	  @   3600 IN A 192.0.2.1
+//	- www 3600 IN A 192.0.2.1
+//	+ www  300 IN A 192.0.2.9
+//	0 to create, 1 to update, 0 to delete, 1 unchanged
+type DiffWriter struct {
+	// Color will wrap the lines with terminal color codes
+	Color bool
+	// Unchanged will also print the records that are not changed
+	Unchanged bool
+	// Summary will print a summary line after the diff
+	Summary bool
+}
+
+type diffLine struct {
+	state  ChangeState
+	record *libdns.RR
+}
+
+// WriteDiff writes the changes with a summary line to the given writer.
+func WriteDiff(writer io.Writer, change ChangeList) error {
+	return (&DiffWriter{Summary: true}).Write(writer, change)
+}
+
+func (d *DiffWriter) Write(writer io.Writer, change ChangeList) error {
+
+	var state = Delete | Create
+
+	if d.Unchanged {
+		state |= NoChange
+	}
+
+	var keys = make([]string, 0)
+	var sets = make(map[string][]*diffLine)
+
+	for x, record := range change.IterateStates(state) {
+		var key = strings.ToLower(record.Name) + "\x00" + record.Type
+
+		if _, ok := sets[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		sets[key] = append(sets[key], &diffLine{state: x, record: record})
+	}
+
+	var records = make([]*libdns.RR, 0)
+
+	for _, key := range keys {
+		for _, line := range sets[key] {
+			records = append(records, line.record)
+		}
+	}
+
+	var formatted = FormatRecords(records)
+	var idx = 0
+
+	for _, key := range keys {
+		for _, state := range []ChangeState{Delete, Create, NoChange} {
+			for i, line := range sets[key] {
+				if line.state != state {
+					continue
+				}
+				if err := d.writeLine(writer, state, formatted[idx+i]); err != nil {
+					return err
+				}
+			}
+		}
+		idx += len(sets[key])
+	}
+
+	if d.Summary {
+		if _, err := fmt.Fprintln(writer, Summary(change)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DiffWriter) writeLine(writer io.Writer, state ChangeState, line string) error {
+
+	var prefix, color = " ", ""
+
+	switch state {
+	case Delete:
+		prefix, color = "-", colorRed
+	case Create:
+		prefix, color = "+", colorGreen
+	}
+
+	if false == d.Color || "" == color {
+		_, err := fmt.Fprintf(writer, "%s %s\n", prefix, line)
+		return err
+	}
+
+	_, err := fmt.Fprintf(writer, "%s%s %s%s\n", color, prefix, line, colorReset)
+
+	return err
+}
+
+// Summary returns a compact summary line of the given changes, for example
+// "2 to create, 1 to update, 1 to delete, 10 unchanged"
+func Summary(change ChangeList) string {
+
+	var count = make(map[ChangeState]int)
+
+	for state := range change.IterateStates(NoChange | Delete | Create | Update) {
+		count[state]++
+	}
+
+	for range change.IterateUpdates() {
+		count[Update]++
+	}
+
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged", count[Create], count[Update], count[Delete], count[NoChange])
+}
+
+// FormatRecord formats a record in zone file syntax like "www 3600 IN A 192.0.2.1"
+func FormatRecord(record *libdns.RR) string {
+	return FormatRecords([]*libdns.RR{record})[0]
+}
+
+// FormatRecords formats the records in zone file syntax with the columns
+// aligned over all records.
+func FormatRecords(records []*libdns.RR) []string {
+
+	var widths [3]int
+	var lines = make([]string, len(records))
+
+	for _, record := range records {
+		widths[0] = max(widths[0], len(record.Name))
+		widths[1] = max(widths[1], len(formatTTL(record.TTL)))
+		widths[2] = max(widths[2], len(record.Type))
+	}
+
+	for i, record := range records {
+		lines[i] = strings.TrimRight(fmt.Sprintf("%-*s %*s IN %-*s %s", widths[0], record.Name, widths[1], formatTTL(record.TTL), widths[2], record.Type, record.Data), " ")
+	}
+
+	return lines
+}
+
+func formatTTL(ttl time.Duration) string {
+	return strconv.FormatInt(int64(ttl/time.Second), 10)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestDiffWriter(t *testing.T) {
+
+	var change = NewChangeList(0, 4)
+
+	change.addRecord(libdns.RR{Name: "@", Type: "A", TTL: time.Hour, Data: "192.0.2.1"}, NoChange)
+	change.addUpdate(libdns.RR{Name: "www", Type: "A", TTL: time.Hour, Data: "192.0.2.1"}, libdns.RR{Name: "www", Type: "A", TTL: 300 * time.Second, Data: "192.0.2.9"})
+	change.addRecord(libdns.RR{Name: "mail", Type: "TXT", TTL: time.Hour, Data: "v=spf1 -all"}, Delete)
+	change.addRecord(libdns.RR{Name: "WWW", Type: "AAAA", TTL: time.Hour, Data: "2001:db8::1"}, Create)
+	change.freeze()
+
+	var tests = []struct {
+		writer   *DiffWriter
+		expected string
+	}{
+		{
+			&DiffWriter{Unchanged: true, Summary: true},
+			"" +
+				"  @    3600 IN A    192.0.2.1\n" +
+				"- www  3600 IN A    192.0.2.1\n" +
+				"+ www   300 IN A    192.0.2.9\n" +
+				"- mail 3600 IN TXT  v=spf1 -all\n" +
+				"+ WWW  3600 IN AAAA 2001:db8::1\n" +
+				"1 to create, 1 to update, 1 to delete, 1 unchanged\n",
+		},
+		{
+			&DiffWriter{},
+			"" +
+				"- www  3600 IN A    192.0.2.1\n" +
+				"+ www   300 IN A    192.0.2.9\n" +
+				"- mail 3600 IN TXT  v=spf1 -all\n" +
+				"+ WWW  3600 IN AAAA 2001:db8::1\n",
+		},
+		{
+			&DiffWriter{Color: true},
+			"" +
+				"\033[31m- www  3600 IN A    192.0.2.1\033[0m\n" +
+				"\033[32m+ www   300 IN A    192.0.2.9\033[0m\n" +
+				"\033[31m- mail 3600 IN TXT  v=spf1 -all\033[0m\n" +
+				"\033[32m+ WWW  3600 IN AAAA 2001:db8::1\033[0m\n",
+		},
+	}
+
+	for _, test := range tests {
+		var buf = new(bytes.Buffer)
+
+		if err := test.writer.Write(buf, change); err != nil {
+			t.Fatal(err)
+		}
+
+		if buf.String() != test.expected {
+			t.Errorf("unexpected diff:\n%s\nexpected:\n%s", buf.String(), test.expected)
+		}
+	}
+}
@@ -3,3 +3,8 @@ module github.com/pbergman/provider
 go 1.24.4
 
 require github.com/libdns/libdns v1.1.1
+
+require (
+	golang.org/x/net v0.50.0
+	golang.org/x/text v0.34.0 // indirect
+)
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidIDN is used when a name holds a label that cannot be converted
+// between the Unicode form (U-label) and the ASCII form (A-label).
+var ErrInvalidIDN = errors.New("invalid internationalized domain name")
+
+const acePrefix = "xn--"
+
+// ToASCII converts the labels of the name that hold non ASCII characters
+// to A-labels (like "bücher" to "xn--bcher-kva"), other labels are returned
+// as is. The Unicode labels are mapped (lowercased and normalized) as
+// described in UTS #46 before they are converted.
+func ToASCII(name string) (string, error) {
+	return mapLabels(name, func(label string) (string, error) {
+
+		if isASCII(label) {
+			return label, nil
+		}
+
+		encoded, err := idna.Lookup.ToASCII(label)
+
+		if err != nil {
+			return "", err
+		}
+
+		if len(encoded) > 63 {
+			return "", errors.New("label exceeds 63 characters")
+		}
+
+		return encoded, nil
+	})
+}
+
+// ToUnicode converts the A-labels of the name to U-labels (like
+// "xn--bcher-kva" to "bücher"), other labels are returned as is.
+func ToUnicode(name string) (string, error) {
+	return mapLabels(name, func(label string) (string, error) {
+
+		if len(label) < len(acePrefix) || false == strings.EqualFold(label[:len(acePrefix)], acePrefix) {
+			return label, nil
+		}
+
+		if len(label) == len(acePrefix) {
+			return "", errors.New("empty A-label")
+		}
+
+		return idna.Lookup.ToUnicode(strings.ToLower(label))
+	})
+}
+
+func mapLabels(name string, fn func(string) (string, error)) (string, error) {
+
+	if isASCII(name) && false == strings.Contains(strings.ToLower(name), acePrefix) {
+		return name, nil
+	}
+
+	var labels = strings.Split(name, ".")
+
+	for i, label := range labels {
+		x, err := fn(label)
+
+		if err != nil {
+			return "", fmt.Errorf("%w: label \"%s\" of \"%s\": %s", ErrInvalidIDN, label, name, err)
+		}
+
+		labels[i] = x
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+// punycode samples of RFC 3492 section 7.1
+func TestPunycode(t *testing.T) {
+
+	var tests = []struct {
+		unicode string
+		ascii   string
+	}{
+		{"ليهمابتكلموشعربي؟", "xn--egbpdaj6bu4bxfgehfvwxn"},
+		{"他们为什么不说中文", "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		{"למההםפשוטלאמדבריםעברית", "xn--4dbcagdahymbxekheh6e0a7fei0b"},
+		{"他們爲什麽不說中文", "xn--ihqwctvzc91f659drss3x8bo0yb"},
+	}
+
+	for _, test := range tests {
+		encoded, err := ToASCII(test.unicode)
+
+		if err != nil || encoded != test.ascii {
+			t.Errorf("ToASCII(%q) = %q, %v, expected %q", test.unicode, encoded, err, test.ascii)
+		}
+
+		decoded, err := ToUnicode(test.ascii)
+
+		if err != nil || decoded != test.unicode {
+			t.Errorf("ToUnicode(%q) = %q, %v, expected %q", test.ascii, decoded, err, test.unicode)
+		}
+	}
+}
+
+func TestToASCII(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		expected string
+		err      bool
+	}{
+		{"example.com.", "example.com.", false},
+		{"bücher", "xn--bcher-kva", false},
+		{"Bücher.Example.", "xn--bcher-kva.Example.", false},
+		{"BÜCHER", "xn--bcher-kva", false},
+		{"bu\u0308cher", "xn--bcher-kva", false},
+		{"Bu\u0308cher.example.", "xn--bcher-kva.example.", false},
+		{"www.münchen.de", "www.xn--mnchen-3ya.de", false},
+		{"xn--bcher-kva", "xn--bcher-kva", false},
+		{"@", "@", false},
+		{strings.Repeat("ü", 64), "", true},
+	}
+
+	for _, test := range tests {
+		name, err := ToASCII(test.name)
+
+		if test.err {
+			if false == errors.Is(err, ErrInvalidIDN) {
+				t.Errorf("ToASCII(%q) expected an error wrapping ErrInvalidIDN, got %v", test.name, err)
+			}
+			continue
+		}
+
+		if err != nil || name != test.expected {
+			t.Errorf("ToASCII(%q) = %q, %v, expected %q", test.name, name, err, test.expected)
+		}
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		expected string
+		err      bool
+	}{
+		{"example.com.", "example.com.", false},
+		{"xn--bcher-kva", "bücher", false},
+		{"XN--BCHER-KVA.example.", "bücher.example.", false},
+		{"www.xn--mnchen-3ya.de", "www.münchen.de", false},
+		{"bücher", "bücher", false},
+		{"xn--ü", "", true},
+		{"xn--", "", true},
+		{"www.xn--.de", "", true},
+		{"xn--bcher-kv!", "", true},
+		{"xn--99999999999999999999", "", true},
+	}
+
+	for _, test := range tests {
+		name, err := ToUnicode(test.name)
+
+		if test.err {
+			if false == errors.Is(err, ErrInvalidIDN) {
+				t.Errorf("ToUnicode(%q) expected an error wrapping ErrInvalidIDN, got %q, %v", test.name, name, err)
+			}
+			continue
+		}
+
+		if err != nil || name != test.expected {
+			t.Errorf("ToUnicode(%q) = %q, %v, expected %q", test.name, name, err, test.expected)
+		}
+	}
+}
+
+func TestSetRecordsMatchesIDN(t *testing.T) {
+
+	var tests = []struct {
+		existing string
+		input    string
+	}{
+		{"xn--bcher-kva", "bücher"},
+		{"bücher", "xn--bcher-kva"},
+		{"xn--bcher-kva", "Bücher.example.com."},
+		{"xn--bcher-kva", "XN--BCHER-KVA"},
+	}
+
+	for _, test := range tests {
+
+		var client = &memoryClient{
+			records: []libdns.Record{libdns.TXT{Name: test.existing, Text: "x"}},
+		}
+
+		created, err := SetRecords(context.Background(), nil, client, "example.com.", []libdns.Record{
+			libdns.TXT{Name: test.input, Text: "x"},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(created) != 0 || len(client.records) != 1 {
+			t.Errorf("expected %q to match %q, got %v created and %v in zone", test.input, test.existing, created, client.records)
+		}
+	}
+}
+
+func TestFindZoneReturnsALabels(t *testing.T) {
+
+	var client = &zoneClient{domains: []string{"bücher.de"}}
+
+	zone, name, err := FindZone(context.Background(), client, "www.xn--bcher-kva.de.")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if "xn--bcher-kva.de." != zone.Name || "www" != name {
+		t.Fatalf("unexpected zone %q and name %q", zone.Name, name)
+	}
+
+	zones, err := ListZones(context.Background(), nil, &zoneClient{domains: []string{"bücher.de"}, unicode: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if "xn--bcher-kva.de." != zones[0].Name {
+		t.Fatalf("unexpected zone %q", zones[0].Name)
+	}
+}
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"iter"
-	"strings"
 
 	"github.com/libdns/libdns"
 )
@@ -17,38 +16,39 @@ func RecordIterator(records *[]libdns.Record) iter.Seq2[*libdns.Record, libdns.R
 	}
 }
 
-func lookup(item *libdns.RR, records *[]libdns.Record, lookup func(a, b *libdns.RR) bool) *libdns.Record {
-	next, stop := iter.Pull2(RecordIterator(records))
+// IsInList reports whether the records hold a record that is the same as the
+// given item according to the DefaultMatcher. This scans the whole list, so
+// use a RecordIndex when checking multiple records against the same list.
+func IsInList(item *libdns.RR, records *[]libdns.Record, ttl bool) bool {
+	return IsInListWithMatcher(DefaultMatcher, item, records, ttl)
+}
 
-	defer stop()
+// IsInListWithMatcher is like IsInList but compares the records with the
+// given matcher.
+func IsInListWithMatcher(matcher Matcher, item *libdns.RR, records *[]libdns.Record, ttl bool) bool {
 
-	for {
-		origin, check, ok := next()
+	var expected = matcher.Canonical(*item)
 
-		if !ok {
-			return nil
-		}
-
-		if lookup(item, &check) {
-			return origin
+	for i, c := 0, len(*records); i < c; i++ {
+		if x := matcher.Canonical((*records)[i].RR()); isSameCanonical(&expected, &x, ttl) {
+			return true
 		}
 	}
-}
 
-func lookupByNameAndType(item *libdns.RR, records *[]libdns.Record) *libdns.Record {
-	return lookup(item, records, func(a, b *libdns.RR) bool {
-		return strings.EqualFold(a.Name, b.Name) && a.Type == b.Type
-	})
+	return false
 }
 
-func IsInList(item *libdns.RR, records *[]libdns.Record, ttl bool) bool {
-	return nil != lookup(item, records, func(a, b *libdns.RR) bool {
-		return strings.EqualFold(a.Name, b.Name) && a.Type == b.Type && a.Data == b.Data && (false == ttl || a.TTL == b.TTL)
-	})
-}
+// pairUpdate will return the position of the first record in the index
+// that has the same name and type as the given record and is not paired
+// yet, or -1 when none.
+func pairUpdate(record libdns.Record, index *RecordIndex, paired []bool) int {
+	var rr = record.RR()
+
+	for _, i := range index.Set(&rr) {
+		if false == paired[i] {
+			return i
+		}
+	}
 
-func isEligibleForRemoval(item *libdns.RR, records *[]libdns.Record) bool {
-	return nil != lookup(item, records, func(a, b *libdns.RR) bool {
-		return strings.EqualFold(a.Name, b.Name) && (b.Type == "" || a.Type == b.Type) && (b.Data == "" || a.Data == b.Data) && (b.TTL == 0 || a.TTL == b.TTL)
-	})
+	return -1
 }
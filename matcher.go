@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// Matcher defines when two records are considered the same. Instead of
+// comparing two records, a Matcher returns the canonical form of a record
+// so records can be indexed. Records are the same when the canonical name,
+// type and data are equal, the TTL is compared separately.
+//
+// Fields that are empty should be kept empty, because those are used as
+// wildcards by DeleteRecords.
+type Matcher interface {
+	Canonical(record libdns.RR) libdns.RR
+}
+
+// MatcherFunc is an adapter to use a function as Matcher
+type MatcherFunc func(record libdns.RR) libdns.RR
+
+func (fn MatcherFunc) Canonical(record libdns.RR) libdns.RR {
+	return fn(record)
+}
+
+// DefaultMatcher compares names case-insensitive, where internationalized
+// names are compared as A-labels, and the data by the
+// semantics of the record type, see CanonicalData.
+var DefaultMatcher Matcher = MatcherFunc(func(record libdns.RR) libdns.RR {
+	record.Name = canonicalName(record.Name)
+	record.Data = CanonicalData(record)
+	return record
+})
+
+// ExactMatcher compares names case-insensitive and the type and data
+// as is.
+var ExactMatcher Matcher = MatcherFunc(func(record libdns.RR) libdns.RR {
+	record.Name = canonicalName(record.Name)
+	return record
+})
+
+// canonicalName returns the name lowercased with A-labels
+func canonicalName(name string) string {
+
+	if ascii, err := ToASCII(name); nil == err {
+		name = ascii
+	}
+
+	return strings.ToLower(name)
+}
+
+// MatcherAware can be implemented by a Client that returns the data of
+// records in a different but equivalent form than given by the caller, so
+// the helpers will use the returned Matcher instead of DefaultMatcher when
+// comparing records. Callers can override it per call with WithMatcher.
+type MatcherAware interface {
+	Matcher() Matcher
+}
+
+// IsSameRecord reports whether both records are the same according to the
+// given matcher, including the TTL when ttl is true.
+func IsSameRecord(matcher Matcher, a, b *libdns.RR, ttl bool) bool {
+	var x, y = matcher.Canonical(*a), matcher.Canonical(*b)
+
+	return isSameCanonical(&x, &y, ttl)
+}
+
+func isSameCanonical(a, b *libdns.RR, ttl bool) bool {
+	return a.Name == b.Name && a.Type == b.Type && a.Data == b.Data && (false == ttl || a.TTL == b.TTL)
+}
+
+type matcherKey struct{}
+
+// WithMatcher returns a context that makes the helpers (like SetRecords and
+// DeleteRecords) compare the records with the given matcher, which takes
+// precedence over the Matcher of a MatcherAware client.
+func WithMatcher(ctx context.Context, matcher Matcher) context.Context {
+	return context.WithValue(ctx, matcherKey{}, matcher)
+}
+
+// matcherOf returns the Matcher of the context, the Matcher of the client or
+// the DefaultMatcher, which compares the names relative to the given zone.
+func matcherOf(ctx context.Context, client Client, zone string) Matcher {
+
+	if x, ok := ctx.Value(matcherKey{}).(Matcher); ok && nil != x {
+		return zoneMatcher(zone, x)
+	}
+
+	if x, ok := client.(MatcherAware); ok && nil != x.Matcher() {
+		return zoneMatcher(zone, x.Matcher())
+	}
+
+	return zoneMatcher(zone, DefaultMatcher)
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestWithMatcher(t *testing.T) {
+
+	var client = &memoryClient{
+		records: []libdns.Record{libdns.RR{Name: "a", Type: "AAAA", Data: "2001:db8::1"}},
+	}
+
+	var deletes = []libdns.Record{libdns.RR{Name: "a", Type: "AAAA", Data: "2001:0db8::0001"}}
+
+	removed, err := DeleteRecords(WithMatcher(context.Background(), ExactMatcher), nil, client, "example.com.", deletes)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if 0 != len(removed) || 1 != len(client.records) {
+		t.Fatalf("expected the exact matcher to keep the record, removed %v", removed)
+	}
+
+	if removed, err = DeleteRecords(context.Background(), nil, client, "example.com.", deletes); err != nil || 1 != len(removed) {
+		t.Fatalf("expected the default matcher to remove the record, removed %v (%v)", removed, err)
+	}
+}
+
+func TestIsInListWithMatcher(t *testing.T) {
+
+	var records = []libdns.Record{libdns.RR{Name: "A", Type: "AAAA", Data: "2001:0db8::0001"}}
+	var item = &libdns.RR{Name: "a", Type: "AAAA", Data: "2001:db8::1"}
+
+	if false == IsInList(item, &records, false) {
+		t.Fatal("expected the default matcher to find the record")
+	}
+
+	if IsInListWithMatcher(ExactMatcher, item, &records, false) {
+		t.Fatal("expected the exact matcher not to find the record")
+	}
+}
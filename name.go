@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrNameOutsideZone is used when the name of a record is not part of the zone
+var ErrNameOutsideZone = errors.New("name is outside of the zone")
+
+// NameError describes a record name that is not part of the zone
+type NameError struct {
+	Name string
+	Zone string
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("name \"%s\" is outside of zone \"%s\"", e.Name, e.Zone)
+}
+
+func (e *NameError) Unwrap() error {
+	return ErrNameOutsideZone
+}
+
+// relativeName returns the name relative to the zone with A-labels, where
+// the apex is returned as "@". Names with a trailing dot, or that end with
+// the zone without trailing dot (see libdns.RelativeName), are handled as
+// FQDN and a NameError is returned when those are not part of the zone.
+func relativeName(name, zone string) (string, error) {
+
+	if "" == name || "@" == name {
+		return "@", nil
+	}
+
+	var original = name
+
+	name, err := ToASCII(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	var origin = normalizeZoneKey(zone)
+
+	if "" == origin {
+		return name, nil
+	}
+
+	var fqdn = strings.TrimSuffix(name, ".")
+	var suffix = "." + origin
+
+	if strings.EqualFold(fqdn, origin) {
+		return "@", nil
+	}
+
+	if len(fqdn) > len(suffix) && strings.EqualFold(fqdn[len(fqdn)-len(suffix):], suffix) {
+		return fqdn[:len(fqdn)-len(suffix)], nil
+	}
+
+	if strings.HasSuffix(name, ".") {
+		return "", &NameError{Name: original, Zone: zone}
+	}
+
+	return name, nil
+}
+
+// clientName returns the name relative to the zone, in the form expected
+// by the client (see ClientCapabilities.UnicodeNames).
+func clientName(client any, name, zone string) (string, error) {
+
+	name, err := relativeName(name, zone)
+
+	if err != nil || false == unicodeNames(client) {
+		return name, err
+	}
+
+	return ToUnicode(name)
+}
+
+// normalizeNames returns the records with the names relative to the zone in
+// the form expected by the client, a RecordError with the given state is
+// returned for names outside the zone or invalid internationalized names.
+func normalizeNames(client any, zone string, records []libdns.Record, state ChangeState) ([]libdns.Record, error) {
+
+	var normalized = make([]libdns.Record, len(records))
+
+	for i, c := 0, len(records); i < c; i++ {
+
+		var rr = records[i].RR()
+
+		name, err := clientName(client, rr.Name, zone)
+
+		if err != nil {
+			return nil, &RecordError{Record: &rr, State: state, Err: err}
+		}
+
+		if normalized[i], err = withName(records[i], name); err != nil {
+			return nil, err
+		}
+	}
+
+	return normalized, nil
+}
+
+// zoneMatcher returns a Matcher that compares the names relative to the
+// zone and the targets resolved against the zone, before the records are
+// compared by the given matcher.
+func zoneMatcher(zone string, matcher Matcher) Matcher {
+	return MatcherFunc(func(record libdns.RR) libdns.RR {
+		if name, err := relativeName(record.Name, zone); nil == err {
+			record.Name = name
+		}
+		return matcher.Canonical(absoluteTarget(record, zone))
+	})
+}
+
+// relativeStream returns the records with the names relative to the zone in
+// the form expected by the client, names that cannot be made relative are
+// returned as is.
+func relativeStream(client any, zone string, records iter.Seq2[libdns.Record, error]) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+		for record, err := range records {
+
+			if nil == err {
+				if name, x := clientName(client, record.RR().Name, zone); nil == x {
+					record, err = withName(record, name)
+				}
+			}
+
+			if false == yield(record, err) || nil != err {
+				return
+			}
+		}
+	}
+}
+
+// withName returns the record with the given name, keeping the provider
+// data of the record. Records that are not a libdns type cannot be renamed
+// and are returned as is.
+func withName(record libdns.Record, name string) (libdns.Record, error) {
+
+	var rr = record.RR()
+
+	if rr.Name == name {
+		return record, nil
+	}
+
+	rr.Name = name
+
+	x, ok, err := withRR(record, rr)
+
+	if !ok {
+		return record, nil
+	}
+
+	return x, err
+}
+
+// withRR returns the record with the name and TTL of the given RR, keeping
+// the type and provider data of the record. False is returned when the
+// record is not a libdns type and cannot be changed.
+func withRR(record libdns.Record, rr libdns.RR) (libdns.Record, bool, error) {
+
+	switch v := record.(type) {
+	case libdns.RR:
+		return rr, true, nil
+	case libdns.Address:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.CNAME:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.NS:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.MX:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.TXT:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.CAA:
+		v.Name, v.TTL = rr.Name, rr.TTL
+		return v, true, nil
+	case libdns.SRV:
+		// the name of the record also holds the service and transport
+		parsed, err := rr.Parse()
+
+		if err != nil {
+			return nil, true, err
+		}
+
+		var x = parsed.(libdns.SRV)
+		x.ProviderData = v.ProviderData
+		return x, true, nil
+	case libdns.ServiceBinding:
+		// the name of the record also holds the scheme and port
+		parsed, err := rr.Parse()
+
+		if err != nil {
+			return nil, true, err
+		}
+
+		var x = parsed.(libdns.ServiceBinding)
+		x.ProviderData = v.ProviderData
+		return x, true, nil
+	}
+
+	return record, false, nil
+}
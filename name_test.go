@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestRelativeName(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		expected string
+		err      error
+	}{
+		{"", "@", nil},
+		{"@", "@", nil},
+		{"example.com.", "@", nil},
+		{"Example.COM", "@", nil},
+		{"www", "www", nil},
+		{"www.example.com.", "www", nil},
+		{"www.example.com", "www", nil},
+		{"www.example.net.", "", ErrNameOutsideZone},
+		{"bücher.example.com.", "xn--bcher-kva", nil},
+	}
+
+	for _, test := range tests {
+		name, err := relativeName(test.name, "example.com.")
+
+		if nil != test.err {
+			if false == errors.Is(err, test.err) {
+				t.Errorf("relativeName(%q) expected an error wrapping %v, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil || name != test.expected {
+			t.Errorf("relativeName(%q) = %q, %v, expected %q", test.name, name, err, test.expected)
+		}
+	}
+}
+
+func TestNameOutsideZone(t *testing.T) {
+
+	_, err := SetRecords(context.Background(), nil, &memoryClient{}, "example.com.", []libdns.Record{
+		libdns.RR{Name: "www.example.net.", Type: "TXT", Data: "x"},
+	})
+
+	var name *NameError
+	var record *RecordError
+
+	if false == errors.Is(err, ErrNameOutsideZone) || false == errors.As(err, &name) || false == errors.As(err, &record) {
+		t.Fatalf("expected a RecordError wrapping a NameError, got %v", err)
+	}
+
+	if "www.example.net." != name.Name || Create != record.State {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/libdns/libdns"
+)
+
+// ChangeOrder defines the order in which the changes of a ChangeList should
+// be applied, see IterateOrdered.
+type ChangeOrder uint8
+
+const (
+	// DeleteFirst will remove records before new records are created
+	DeleteFirst ChangeOrder = iota
+	// CreateFirst will create records before old records are removed,
+	// so that a name never resolves to nothing while records are replaced.
+	CreateFirst
+	// Interleaved will apply the changes per set (same name and type)
+	// where new records are created before the old records of the set
+	// are removed, except for types that only allow a single record
+	// (like CNAME) which will remove the old record first. Sets that
+	// only remove records are applied before all other sets.
+	Interleaved
+)
+
+var changeOrderNames = map[ChangeOrder]string{
+	DeleteFirst: "delete-first",
+	CreateFirst: "create-first",
+	Interleaved: "interleaved",
+}
+
+// singletonTypes holds the record types of which only one record can exist
+// for a name, these cannot be created before the old record is removed.
+var singletonTypes = map[string]bool{
+	"CNAME": true,
+	"DNAME": true,
+	"SOA":   true,
+}
+
+func (o ChangeOrder) String() string {
+	if name, ok := changeOrderNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("ChangeOrder(%d)", o)
+}
+
+func (o ChangeOrder) MarshalText() ([]byte, error) {
+	if _, ok := changeOrderNames[o]; !ok {
+		return nil, fmt.Errorf("invalid change order %d", o)
+	}
+	return []byte(o.String()), nil
+}
+
+func (o *ChangeOrder) UnmarshalText(text []byte) error {
+	for order, name := range changeOrderNames {
+		if name == string(text) {
+			*o = order
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid change order \"%s\"", text)
+}
+
+// rank returns the position of the state for the given order
+func (o ChangeOrder) rank(state ChangeState, kind string) int {
+
+	var ranks = map[ChangeState]int{Delete: 0, Update: 1, Create: 2, NoChange: 3}
+
+	if o == CreateFirst || (o == Interleaved && false == singletonTypes[kind]) {
+		ranks[Delete], ranks[Create] = 2, 0
+	}
+
+	return ranks[state]
+}
+
+func (c *changes) Order() ChangeOrder {
+	return c.order
+}
+
+func (c *changes) WithOrder(order ChangeOrder) ChangeList {
+	var list = &changes{
+		records: c.records,
+		state:   c.state,
+		frozen:  true,
+		results: c.results,
+		origins: c.origins,
+		partial: c.partial,
+		order:   order,
+	}
+	return list
+}
+
+func (c *changes) IterateOrdered(state ChangeState) iter.Seq[*ChangeRecord] {
+	return func(yield func(*ChangeRecord) bool) {
+		for _, group := range c.ordered(state) {
+			for _, record := range group.records {
+				if false == yield(record) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// changeGroup holds the ordered records of a set, together with the weight
+// of the set (see setWeight).
+type changeGroup struct {
+	records []*ChangeRecord
+	weight  int
+}
+
+// ordered returns the records selected by the given state in the order
+// of this list. For Interleaved the records are grouped per set, for the
+// other orders a single group is returned.
+func (c *changes) ordered(state ChangeState) []*changeGroup {
+
+	var sets = c.sets()
+
+	if c.order == Interleaved {
+		slices.SortStableFunc(sets, func(a, b *changeSet) int {
+			return setWeight(a) - setWeight(b)
+		})
+	}
+
+	var groups = make([]*changeGroup, 0, len(sets))
+
+	for _, set := range sets {
+		var group = make([]*ChangeRecord, 0, len(set.records))
+
+		for _, record := range set.records {
+			if record.state != Update || Update == (state&Update) {
+				if record.state == (record.state & state) {
+					group = append(group, record)
+				}
+				continue
+			}
+
+			// expanded updates keep the origin of the record they represent
+			record.match(state, func(x ChangeState, rr *libdns.RR) bool {
+				if rr == record.previous {
+					group = append(group, &ChangeRecord{record: rr, state: x, origin: record.previousOrigin})
+				} else {
+					group = append(group, &ChangeRecord{record: rr, state: x, origin: record.origin})
+				}
+				return true
+			})
+		}
+
+		if len(group) > 0 {
+			groups = append(groups, &changeGroup{records: group, weight: setWeight(set)})
+		}
+	}
+
+	if c.order != Interleaved {
+		var records = make([]*ChangeRecord, 0)
+
+		for _, group := range groups {
+			records = append(records, group.records...)
+		}
+
+		groups = []*changeGroup{{records: records}}
+	}
+
+	for _, group := range groups {
+		slices.SortStableFunc(group.records, func(a, b *ChangeRecord) int {
+			return c.order.rank(a.state, a.record.Type) - c.order.rank(b.state, b.record.Type)
+		})
+	}
+
+	return groups
+}
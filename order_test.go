@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestIterateOrderedKeepsOrigin(t *testing.T) {
+
+	var client = &memoryClient{
+		records: []libdns.Record{libdns.TXT{Name: "a", Text: "x", ProviderData: "id-1"}},
+	}
+
+	change, _, err := PlanSet(context.Background(), nil, client, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "a", Text: "y"},
+		libdns.TXT{Name: "b", Text: "y"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count = 0
+
+	for record := range change.IterateOrdered(Delete | Create) {
+		count++
+
+		if record.State() == Delete && "id-1" != record.Origin().(libdns.TXT).ProviderData {
+			t.Fatalf("expected the origin of %s to hold the provider data", FormatRecord(record.Record()))
+		}
+
+		if record.Origin().RR() != *record.Record() {
+			t.Fatalf("expected origin %v to match %v", record.Origin(), record.Record())
+		}
+	}
+
+	if 3 != count {
+		t.Fatalf("expected 3 records, got %d", count)
+	}
+}
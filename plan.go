@@ -0,0 +1,358 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrPlanOutdated is returned by ApplyPlan when the zone changed after
+// the plan was created.
+var ErrPlanOutdated = errors.New("zone records changed since plan was created")
+
+// ErrInvalidPlan is returned by ApplyPlan when the plan is nil or holds
+// no change list.
+var ErrInvalidPlan = errors.New("invalid plan")
+
+type Operation string
+
+const (
+	OperationAppend Operation = "append"
+	OperationSet    Operation = "set"
+	OperationDelete Operation = "delete"
+)
+
+// Plan holds a computed ChangeList together with the zone and operation
+// it was created for, and a fingerprint of the zone records it was
+// computed against. A plan can be encoded as JSON, saved and applied
+// later with ApplyPlan, which will refuse to apply it when the zone
+// has changed in the meantime.
+type Plan struct {
+	Zone        string
+	Operation   Operation
+	Fingerprint string
+	Changes     ChangeList
+}
+
+// NewPlan creates a plan for the given operation, the changes are computed
+// the same way as AppendRecords, SetRecords or DeleteRecords would.
+func NewPlan(ctx context.Context, mutex sync.Locker, client Client, operation Operation, zone string, records []libdns.Record) (*Plan, error) {
+
+	zone, err := normalizeZone(client, zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if unlock := rlock(mutex); nil != unlock {
+		defer unlock()
+	}
+
+	existing, err := GetRecords(ctx, nil, client, zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var plan = &Plan{
+		Zone:        zone,
+		Operation:   operation,
+		Fingerprint: Fingerprint(existing),
+	}
+
+	if operation == OperationAppend || operation == OperationSet {
+		records, err = normalizeRecords(client, zone, records)
+	} else {
+		records, err = normalizeNames(client, zone, records, Delete)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationAppend:
+		plan.Changes, _, err = planAppend(sliceStream(existing), records)
+	case OperationSet:
+		plan.Changes, _, err = planSet(sliceStream(existing), records, matcherOf(ctx, client, zone), false)
+	case OperationDelete:
+		plan.Changes, _, err = planDelete(sliceStream(existing), records, matcherOf(ctx, client, zone), false)
+	default:
+		return nil, fmt.Errorf("unsupported plan operation \"%s\"", operation)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ApplyPlan passes the changes of the plan to the client when the records
+// of the zone still match the fingerprint of the plan, otherwise it will
+// return ErrPlanOutdated. A nil plan or a plan without changes will return
+// ErrInvalidPlan.
+//
+// It returns the records that are created for append and set operations
+// and the removed records for delete operations.
+func ApplyPlan(ctx context.Context, mutex sync.Locker, client Client, plan *Plan) ([]libdns.Record, error) {
+
+	if nil == plan || nil == plan.Changes {
+		return nil, fmt.Errorf("%w: missing change list", ErrInvalidPlan)
+	}
+
+	zone, err := normalizeZone(client, plan.Zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if unlock := lock(mutex); nil != unlock {
+		defer unlock()
+	}
+
+	existing, err := GetRecords(ctx, nil, client, zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if Fingerprint(existing) != plan.Fingerprint {
+		return nil, ErrPlanOutdated
+	}
+
+	var state = Create
+
+	if plan.Operation == OperationDelete {
+		state = Delete
+	}
+
+	var ret = make([]libdns.Record, 0)
+
+	if false == plan.Changes.Has(Delete|Create) {
+		return ret, nil
+	}
+
+	var change = attachOrigins(plan.Changes, existing, matcherOf(ctx, client, zone))
+
+	_, err = client.SetDNSList(ctx, zone, change)
+
+	if nil == err {
+		err = reportedFailure(change)
+	}
+
+	if err != nil {
+		return failure(ctx, client, zone, change, state, err)
+	}
+
+	for _, record := range change.IterateOrigins(state) {
+		ret = append(ret, record)
+	}
+
+	if err := parseRecords(ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// attachOrigins returns a frozen copy of the change where the existing records
+// use the given records as origin, so the provider data (like record ids) that
+// is lost when a plan is encoded is available to the client again.
+func attachOrigins(change ChangeList, records []libdns.Record, matcher Matcher) ChangeList {
+
+	var index = NewRecordIndexWithMatcher(records, matcher)
+	var used = make([]bool, len(records))
+	var find = func(record *libdns.RR, origin libdns.Record) libdns.Record {
+		for _, i := range index.Find(record, true) {
+			if false == used[i] {
+				used[i] = true
+				return index.Record(i)
+			}
+		}
+		return origin
+	}
+
+	var attached = NewChangeList(0, len(change.(*changes).records))
+
+	for _, record := range change.(*changes).records {
+
+		if nil == record {
+			continue
+		}
+
+		switch record.state {
+		case Create:
+			attached.addRecord(record.Origin(), Create)
+		case Update:
+			attached.addUpdate(find(record.previous, record.PreviousOrigin()), record.Origin())
+		default:
+			attached.addRecord(find(record.record, record.Origin()), record.state)
+		}
+	}
+
+	attached.freeze()
+
+	return attached.WithOrder(change.Order())
+}
+
+// Fingerprint returns a hash of the given records which is independent
+// of the order of the records.
+func Fingerprint(records []libdns.Record) string {
+
+	var lines = make([]string, 0, len(records))
+
+	for _, record := range RecordIterator(&records) {
+		lines = append(lines, fmt.Sprintf("%s\t%d\t%s\t%s", strings.ToLower(record.Name), int64(record.TTL/time.Second), record.Type, record.Data))
+	}
+
+	slices.Sort(lines)
+
+	var hash = sha256.New()
+
+	for _, line := range lines {
+		_, _ = hash.Write([]byte(line + "\n"))
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+type planRecord struct {
+	State ChangeState `json:"state"`
+	Name  string      `json:"name"`
+	TTL   int64       `json:"ttl"`
+	Type  string      `json:"type"`
+	Data  string      `json:"data"`
+	// Previous is only set for records marked as Update
+	Previous *planRecord `json:"previous,omitempty"`
+}
+
+func newPlanRecord(state ChangeState, record *libdns.RR) *planRecord {
+	return &planRecord{
+		State: state,
+		Name:  record.Name,
+		TTL:   int64(record.TTL / time.Second),
+		Type:  record.Type,
+		Data:  record.Data,
+	}
+}
+
+func (p *planRecord) rr() libdns.RR {
+	return libdns.RR{
+		Name: p.Name,
+		TTL:  time.Duration(p.TTL) * time.Second,
+		Type: p.Type,
+		Data: p.Data,
+	}
+}
+
+type planDocument struct {
+	Zone        string        `json:"zone"`
+	Operation   Operation     `json:"operation"`
+	Fingerprint string        `json:"fingerprint"`
+	Order       ChangeOrder   `json:"order"`
+	Changes     []*planRecord `json:"changes"`
+}
+
+func (p *Plan) records() []*planRecord {
+
+	var records = make([]*planRecord, 0)
+
+	if nil == p.Changes {
+		return records
+	}
+
+	var changes = p.Changes.(*changes)
+
+	for _, change := range changes.records {
+
+		if nil == change {
+			continue
+		}
+
+		var record = newPlanRecord(change.state, change.record)
+
+		if change.state == Update {
+			record.Previous = newPlanRecord(Delete, change.previous)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	var document = &planDocument{
+		Zone:        p.Zone,
+		Operation:   p.Operation,
+		Fingerprint: p.Fingerprint,
+		Changes:     p.records(),
+	}
+
+	if nil != p.Changes {
+		document.Order = p.Changes.Order()
+	}
+
+	return json.Marshal(document)
+}
+
+func (p *Plan) UnmarshalJSON(data []byte) error {
+
+	var document planDocument
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	var change = NewChangeList(0, len(document.Changes))
+
+	for _, record := range document.Changes {
+
+		if record.State != Update {
+			change.addRecord(record.rr(), record.State)
+			continue
+		}
+
+		if nil == record.Previous {
+			return fmt.Errorf("missing previous record for update of \"%s\"", record.Name)
+		}
+
+		change.addUpdate(record.Previous.rr(), record.rr())
+	}
+
+	change.freeze()
+
+	p.Zone = document.Zone
+	p.Operation = document.Operation
+	p.Fingerprint = document.Fingerprint
+	p.Changes = change.WithOrder(document.Order)
+
+	return nil
+}
+
+// String returns a human-readable representation of the plan
+func (p *Plan) String() string {
+
+	var buf = new(bytes.Buffer)
+
+	_, _ = fmt.Fprintf(buf, "zone:        %s\n", p.Zone)
+	_, _ = fmt.Fprintf(buf, "operation:   %s\n", p.Operation)
+	_, _ = fmt.Fprintf(buf, "fingerprint: %s\n\n", p.Fingerprint)
+
+	if nil != p.Changes {
+		_ = WriteDiff(buf, p.Changes)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+// idRecordClient is a RecordClient that removes records by their id
+type idRecordClient struct {
+	records []libdns.Record
+	deleted []any
+}
+
+func (c *idRecordClient) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+	return slices.Clone(c.records), nil
+}
+
+func (c *idRecordClient) CreateRecord(ctx context.Context, domain string, record libdns.Record) error {
+	c.records = append(c.records, record)
+	return nil
+}
+
+func (c *idRecordClient) DeleteRecord(ctx context.Context, domain string, record libdns.Record) error {
+	var id = record.(libdns.TXT).ProviderData
+
+	c.deleted = append(c.deleted, id)
+	c.records = slices.DeleteFunc(c.records, func(x libdns.Record) bool {
+		return x.(libdns.TXT).ProviderData == id
+	})
+
+	return nil
+}
+
+func TestApplyPlanAttachesProviderData(t *testing.T) {
+
+	var records = &idRecordClient{
+		records: []libdns.Record{
+			libdns.TXT{Name: "a", Text: "x", ProviderData: "id-1"},
+			libdns.TXT{Name: "b", Text: "x", ProviderData: "id-2"},
+		},
+	}
+
+	var client = NewRecordClient(records, nil)
+
+	plan, err := NewPlan(context.Background(), nil, client, OperationDelete, "example.com.", []libdns.Record{
+		libdns.RR{Name: "a", Type: "TXT"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(plan)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Plan
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := ApplyPlan(context.Background(), nil, client, &decoded)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 1 || len(records.deleted) != 1 || "id-1" != records.deleted[0] {
+		t.Fatalf("expected record id-1 to be removed, got %v", records.deleted)
+	}
+}
+
+func TestApplyPlanInvalid(t *testing.T) {
+
+	for _, plan := range []*Plan{nil, {Zone: "example.com.", Operation: OperationSet}} {
+		if _, err := ApplyPlan(context.Background(), nil, &memoryClient{}, plan); false == errors.Is(err, ErrInvalidPlan) {
+			t.Fatalf("expected ErrInvalidPlan, got %v", err)
+		}
+	}
+}
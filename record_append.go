@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"iter"
 	"sync"
 
 	"github.com/libdns/libdns"
@@ -14,47 +15,98 @@ import (
 // issues are found.
 func AppendRecords(ctx context.Context, mutex sync.Locker, client Client, zone string, records []libdns.Record) ([]libdns.Record, error) {
 
-	if unlock := lock(mutex); unlock != nil {
-		defer unlock()
+	zone, err := normalizeZone(client, zone)
+
+	if err != nil {
+		return nil, err
 	}
 
-	existing, err := GetRecords(ctx, nil, client, zone)
+	records, err = normalizeRecords(client, zone, records)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var change = NewChangeList(0, len(existing)+len(records))
-
-	for _, record := range RecordIterator(&existing) {
-		change.addRecord(&record, NoChange)
+	if unlock := lock(mutex); unlock != nil {
+		defer unlock()
 	}
 
-	for _, record := range RecordIterator(&records) {
-		change.addRecord(&record, Create)
+	change, _, err := planAppend(streamRecords(ctx, client, zone), records)
+
+	if err != nil {
+		return nil, err
 	}
 
 	items, err := client.SetDNSList(ctx, zone, change)
 
+	if nil == err {
+		err = reportedFailure(change)
+	}
+
 	if err != nil {
-		return nil, err
+		return failure(ctx, client, zone, change, Create, err)
 	}
 
-	if nil == items {
-		items, err = GetRecords(ctx, nil, client, zone)
+	var existing = NewRecordIndexWithMatcher(existingRecords(change), matcherOf(ctx, client, zone))
+	var ret = make([]libdns.Record, 0)
+
+	for item, err := range changedRecords(ctx, client, zone, items, nil) {
 
 		if err != nil {
 			return nil, err
 		}
+
+		if record := item.RR(); false == existing.Contains(&record, false) {
+			ret = append(ret, item)
+		}
 	}
 
-	var ret = make([]libdns.Record, 0)
+	return ret, nil
+}
+
+// PlanAppend returns the ChangeList that AppendRecords would pass to the client
+// together with the records that would be created, without applying it.
+func PlanAppend(ctx context.Context, mutex sync.Locker, client Client, zone string, records []libdns.Record) (ChangeList, []libdns.Record, error) {
 
-	for origin, record := range RecordIterator(&items) {
-		if false == IsInList(&record, &existing, false) {
-			ret = append(ret, *origin)
+	zone, err := normalizeZone(client, zone)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records, err = normalizeRecords(client, zone, records)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if unlock := rlock(mutex); unlock != nil {
+		defer unlock()
+	}
+
+	return planAppend(streamRecords(ctx, client, zone), records)
+}
+
+func planAppend(existing iter.Seq2[libdns.Record, error], records []libdns.Record) (ChangeList, []libdns.Record, error) {
+
+	var change = NewChangeList(0, len(records))
+	var ret = make([]libdns.Record, 0, len(records))
+
+	for record, err := range existing {
+
+		if err != nil {
+			return nil, nil, err
 		}
+
+		change.addRecord(record, NoChange)
 	}
 
-	return ret, nil
+	for _, record := range records {
+		change.addRecord(record, Create)
+		ret = append(ret, record)
+	}
+
+	change.freeze()
+
+	return change, ret, nil
 }
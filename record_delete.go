@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"iter"
 	"sync"
 
 	"github.com/libdns/libdns"
@@ -15,28 +16,30 @@ import (
 // https://github.com/libdns/libdns/blob/master/libdns.go#L228C1-L237C43
 func DeleteRecords(ctx context.Context, mutex sync.Locker, client Client, zone string, deletes []libdns.Record) ([]libdns.Record, error) {
 
-	var unlock = lock(mutex)
+	zone, err := normalizeZone(client, zone)
 
-	if nil != unlock {
-		defer unlock()
+	if err != nil {
+		return nil, err
 	}
 
-	records, err := GetRecords(ctx, nil, client, zone)
+	deletes, err = normalizeNames(client, zone, deletes, Delete)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var change = NewChangeList()
+	var unlock = lock(mutex)
 
-	for _, record := range RecordIterator(&records) {
-		var state = NoChange
+	if nil != unlock {
+		defer unlock()
+	}
 
-		if isEligibleForRemoval(&record, &deletes) {
-			state = Delete
-		}
+	var filter = newRecordFilter(client, deletes)
+
+	change, candidates, err := planDelete(fetchRecords(ctx, client, zone, filter), deletes, matcherOf(ctx, client, zone), nil != filter)
 
-		change.addRecord(&record, state)
+	if err != nil {
+		return nil, err
 	}
 
 	if false == change.Has(Delete) {
@@ -45,8 +48,12 @@ func DeleteRecords(ctx context.Context, mutex sync.Locker, client Client, zone s
 
 	curr, err := client.SetDNSList(ctx, zone, change)
 
+	if nil == err {
+		err = reportedFailure(change)
+	}
+
 	if err != nil {
-		return nil, err
+		return failure(ctx, client, zone, change, Delete, err)
 	}
 
 	if nil != unlock {
@@ -57,21 +64,85 @@ func DeleteRecords(ctx context.Context, mutex sync.Locker, client Client, zone s
 		defer unlock()
 	}
 
-	if nil == curr {
-		curr, err = GetRecords(ctx, nil, client, zone)
+	var index = NewRecordIndexWithMatcher(candidates, matcherOf(ctx, client, zone))
+	var present = make([]bool, len(candidates))
+
+	for item, err := range changedRecords(ctx, client, zone, curr, filter) {
 
 		if err != nil {
 			return nil, err
 		}
+
+		var record = item.RR()
+
+		for _, i := range index.Find(&record, false) {
+			present[i] = true
+		}
 	}
 
 	var removed = make([]libdns.Record, 0)
 
-	for origin, record := range RecordIterator(&records) {
-		if false == IsInList(&record, &curr, false) && isEligibleForRemoval(&record, &deletes) {
-			removed = append(removed, *origin)
+	for i, c := 0, len(candidates); i < c; i++ {
+		if false == present[i] {
+			removed = append(removed, candidates[i])
 		}
 	}
 
 	return removed, nil
 }
+
+// PlanDelete returns the ChangeList that DeleteRecords would pass to the client
+// together with the records that would be removed, without applying it.
+func PlanDelete(ctx context.Context, mutex sync.Locker, client Client, zone string, deletes []libdns.Record) (ChangeList, []libdns.Record, error) {
+
+	zone, err := normalizeZone(client, zone)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deletes, err = normalizeNames(client, zone, deletes, Delete)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if unlock := rlock(mutex); nil != unlock {
+		defer unlock()
+	}
+
+	var filter = newRecordFilter(client, deletes)
+
+	return planDelete(fetchRecords(ctx, client, zone, filter), deletes, matcherOf(ctx, client, zone), nil != filter)
+}
+
+func planDelete(records iter.Seq2[libdns.Record, error], deletes []libdns.Record, matcher Matcher, partial bool) (ChangeList, []libdns.Record, error) {
+
+	var change = NewChangeList()
+	var index = NewRecordIndexWithMatcher(deletes, matcher)
+	var removed = make([]libdns.Record, 0)
+
+	for origin, err := range records {
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var state = NoChange
+
+		if record := origin.RR(); index.Covers(&record) {
+			state = Delete
+			removed = append(removed, origin)
+		}
+
+		change.addRecord(origin, state)
+	}
+
+	if partial {
+		change.markPartial()
+	}
+
+	change.freeze()
+
+	return change, removed, nil
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ErrPartialChangeList is returned when a ChangeList that only holds part of
+// the zone is used to replace the whole zone.
+var ErrPartialChangeList = errors.New("change list only holds part of the zone")
+
+// FilteredClient can be implemented by a Client for APIs that support server
+// side filtering of records. DeleteRecords and SetRecords will then only
+// fetch the records with the names and types touched by the input instead
+// of the whole zone.
+//
+// Because the ChangeList will only hold part of the zone, it will be marked
+// as partial (see ChangeList.IsPartial) and GetList should not be used to
+// replace the whole zone.
+type FilteredClient interface {
+	Client
+	// GetDNSListFiltered returns the records of the given zone that match
+	// one of the names and, when types is not nil, one of the types. The
+	// client may return more records than requested.
+	//
+	// The names are relative to the zone, where the apex is given as "@",
+	// with A-labels (or U-labels, see ClientCapabilities.UnicodeNames) and
+	// lowercase unless ClientCapabilities.CaseSensitiveNames is set. The
+	// types are uppercase (like "TXT").
+	GetDNSListFiltered(ctx context.Context, domain string, names, types []string) ([]libdns.Record, error)
+}
+
+// recordFilter holds the names and types touched by the input records, a nil
+// filter or nil types means that all records or types are needed.
+type recordFilter struct {
+	names []string
+	types []string
+}
+
+// newRecordFilter returns a filter for the given records, or nil when the client
+// does not support filtering or the records cannot be filtered by name.
+func newRecordFilter(client Client, records []libdns.Record) *recordFilter {
+
+	if _, ok := client.(FilteredClient); !ok || len(records) == 0 {
+		return nil
+	}
+
+	// adapters implement FilteredClient but report if the wrapped client does
+	if x, ok := client.(interface{ supportsFilter() bool }); ok && false == x.supportsFilter() {
+		return nil
+	}
+
+	var filter = &recordFilter{
+		names: make([]string, 0),
+		types: make([]string, 0),
+	}
+
+	var lower = false == caseSensitiveNames(client)
+
+	// the records are normalized, so the names are relative
+	// to the zone in the form expected by the client
+	for _, record := range RecordIterator(&records) {
+
+		if "" == record.Name {
+			return nil
+		}
+
+		if lower {
+			record.Name = strings.ToLower(record.Name)
+		}
+
+		if false == slices.Contains(filter.names, record.Name) {
+			filter.names = append(filter.names, record.Name)
+		}
+
+		if "" == record.Type {
+			filter.types = nil
+		}
+
+		if nil != filter.types && false == slices.Contains(filter.types, strings.ToUpper(record.Type)) {
+			filter.types = append(filter.types, strings.ToUpper(record.Type))
+		}
+	}
+
+	return filter
+}
+
+// fetchRecords returns an iterator over the records that match the filter,
+// or all records of the zone when the filter is nil.
+func fetchRecords(ctx context.Context, client Client, zone string, filter *recordFilter) iter.Seq2[libdns.Record, error] {
+
+	if nil == filter {
+		return streamRecords(ctx, client, zone)
+	}
+
+	return func(yield func(libdns.Record, error) bool) {
+		list, err := client.(FilteredClient).GetDNSListFiltered(ctx, zone, filter.names, filter.types)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for record, err := range relativeStream(client, zone, parseStream(sliceStream(list))) {
+			if false == yield(record, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+// filteredClient is a memoryClient that records the filters it is called with
+type filteredClient struct {
+	memoryClient
+	capabilities *ClientCapabilities
+	names        []string
+	types        []string
+}
+
+func (f *filteredClient) GetDNSListFiltered(ctx context.Context, domain string, names, types []string) ([]libdns.Record, error) {
+	f.names, f.types = names, types
+	return f.GetDNSList(ctx, domain)
+}
+
+func (f *filteredClient) Capabilities() *ClientCapabilities {
+	return f.capabilities
+}
+
+func TestRecordFilterNames(t *testing.T) {
+
+	var tests = []struct {
+		capabilities *ClientCapabilities
+		names        []string
+	}{
+		{nil, []string{"www", "@", "xn--bcher-kva"}},
+		{&ClientCapabilities{CaseSensitiveNames: true}, []string{"WWW", "www", "@", "xn--bcher-kva"}},
+		{&ClientCapabilities{UnicodeNames: true}, []string{"www", "@", "bücher"}},
+	}
+
+	for _, test := range tests {
+
+		var client = &filteredClient{capabilities: test.capabilities}
+
+		_, err := DeleteRecords(context.Background(), nil, client, "Example.com.", []libdns.Record{
+			libdns.RR{Name: "WWW.example.com.", Type: "txt"},
+			libdns.RR{Name: "www", Type: "TXT"},
+			libdns.RR{Name: "example.com.", Type: "TXT"},
+			libdns.RR{Name: "Bücher", Type: "TXT"},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if false == slices.Equal(client.names, test.names) || false == slices.Equal(client.types, []string{"TXT"}) {
+			t.Fatalf("unexpected filter %v %v, expected %v", client.names, client.types, test.names)
+		}
+	}
+}
+
+func TestFilteredPlansArePartial(t *testing.T) {
+
+	var client = &filteredClient{}
+	var records = []libdns.Record{libdns.RR{Name: "a", Type: "TXT", Data: "x"}}
+
+	set, _, err := PlanSet(context.Background(), nil, client, "example.com.", records)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remove, _, err := PlanDelete(context.Background(), nil, client, "example.com.", records)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, change := range []ChangeList{set, remove} {
+		if false == change.IsPartial() || false == change.IsFrozen() {
+			t.Fatal("expected a frozen partial change list")
+		}
+	}
+}
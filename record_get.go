@@ -11,31 +11,48 @@ import (
 // that the returned records are properly typed according to their specific RR type.
 func GetRecords(ctx context.Context, mutex sync.Locker, client Client, zone string) ([]libdns.Record, error) {
 
-	if unlock := rlock(mutex); nil != unlock {
-		defer unlock()
-	}
+	var list = make([]libdns.Record, 0)
+
+	for record, err := range StreamRecords(ctx, mutex, client, zone) {
 
-	list, err := client.GetDNSList(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
 
-	if err != nil {
-		return nil, err
+		list = append(list, record)
 	}
 
-	type recordParser interface {
-		Parse() (libdns.Record, error)
+	return list, nil
+}
+
+type recordParser interface {
+	Parse() (libdns.Record, error)
+}
+
+// parseRecord returns the specific RR type for records that support
+// parsing (like libdns.RR), other records are returned as is.
+func parseRecord(record libdns.Record) (libdns.Record, error) {
+
+	if v, ok := record.(recordParser); ok {
+		return v.Parse()
 	}
 
-	for i, c := 0, len(list); i < c; i++ {
-		if v, ok := list[i].(recordParser); ok {
-			x, err := v.Parse()
+	return record, nil
+}
 
-			if err != nil {
-				return nil, err
-			}
+// parseRecords replaces the records that support parsing (like libdns.RR)
+// with their specific RR type.
+func parseRecords(list []libdns.Record) error {
 
-			list[i] = x
+	for i, c := 0, len(list); i < c; i++ {
+		x, err := parseRecord(list[i])
+
+		if err != nil {
+			return err
 		}
+
+		list[i] = x
 	}
 
-	return list, nil
+	return nil
 }
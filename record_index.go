@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"github.com/libdns/libdns"
+)
+
+type recordKey struct {
+	name string
+	typ  string
+}
+
+type indexEntry struct {
+	origin libdns.Record
+	record libdns.RR
+}
+
+type indexSet struct {
+	items []int
+	data  map[string][]int
+}
+
+// RecordIndex indexes records by name and type, with a sub index on the
+// record data, so records can be matched without scanning the whole list.
+// The records are indexed by their canonical form of the Matcher, which is
+// computed once when they are added.
+type RecordIndex struct {
+	entries []indexEntry
+	sets    map[recordKey]*indexSet
+	matcher Matcher
+}
+
+// NewRecordIndex returns an index of the given records that compares
+// records with the DefaultMatcher.
+func NewRecordIndex(records []libdns.Record) *RecordIndex {
+	return NewRecordIndexWithMatcher(records, DefaultMatcher)
+}
+
+// NewRecordIndexWithMatcher returns an index of the given records that
+// compares records by the canonical form of the given Matcher.
+func NewRecordIndexWithMatcher(records []libdns.Record, matcher Matcher) *RecordIndex {
+
+	var index = &RecordIndex{
+		entries: make([]indexEntry, 0, len(records)),
+		sets:    make(map[recordKey]*indexSet),
+		matcher: matcher,
+	}
+
+	for _, record := range records {
+		index.Add(record)
+	}
+
+	return index
+}
+
+// Add adds the record to the index at the position of Len
+func (i *RecordIndex) Add(record libdns.Record) {
+
+	var rr = i.matcher.Canonical(record.RR())
+	var key = recordKey{name: rr.Name, typ: rr.Type}
+	var set, ok = i.sets[key]
+
+	if !ok {
+		set = &indexSet{data: make(map[string][]int)}
+		i.sets[key] = set
+	}
+
+	set.items = append(set.items, len(i.entries))
+	set.data[rr.Data] = append(set.data[rr.Data], len(i.entries))
+
+	i.entries = append(i.entries, indexEntry{origin: record, record: rr})
+}
+
+// Len returns the number of records in the index
+func (i *RecordIndex) Len() int {
+	return len(i.entries)
+}
+
+// Record returns the record at the given position, as it was added
+func (i *RecordIndex) Record(n int) libdns.Record {
+	return i.entries[n].origin
+}
+
+// Set returns the positions of the records with the same name and type
+// as the given record.
+func (i *RecordIndex) Set(record *libdns.RR) []int {
+	var rr = i.matcher.Canonical(*record)
+
+	if set, ok := i.sets[recordKey{name: rr.Name, typ: rr.Type}]; ok {
+		return set.items
+	}
+
+	return nil
+}
+
+// HasSet reports whether the index has records with the same name and
+// type as the given record.
+func (i *RecordIndex) HasSet(record *libdns.RR) bool {
+	return len(i.Set(record)) > 0
+}
+
+// Find returns the positions of the records that have the same name, type
+// and data as the given record, and the same TTL when ttl is true.
+func (i *RecordIndex) Find(record *libdns.RR, ttl bool) []int {
+
+	var rr = i.matcher.Canonical(*record)
+	var set, ok = i.sets[recordKey{name: rr.Name, typ: rr.Type}]
+
+	if !ok {
+		return nil
+	}
+
+	var items = set.data[rr.Data]
+
+	if false == ttl {
+		return items
+	}
+
+	var ret = make([]int, 0, len(items))
+
+	for _, n := range items {
+		if i.entries[n].record.TTL == rr.TTL {
+			ret = append(ret, n)
+		}
+	}
+
+	return ret
+}
+
+// Contains reports whether the index has a record that is the same as the
+// given record, see Find.
+func (i *RecordIndex) Contains(record *libdns.RR, ttl bool) bool {
+	return len(i.Find(record, ttl)) > 0
+}
+
+// Covers reports whether the given record is matched by one of the records
+// in the index, where an empty type, data or TTL of an indexed record will
+// match any value. This follows the rules of the libdns DeleteRecords
+// contract.
+func (i *RecordIndex) Covers(rr *libdns.RR) bool {
+
+	var record = i.matcher.Canonical(*rr)
+
+	for _, typ := range []string{record.Type, ""} {
+
+		set, ok := i.sets[recordKey{name: record.Name, typ: typ}]
+
+		if !ok {
+			continue
+		}
+
+		for _, data := range []string{record.Data, ""} {
+			for _, n := range set.data[data] {
+				if x := i.entries[n].record.TTL; 0 == x || x == record.TTL {
+					return true
+				}
+			}
+
+			if "" == record.Data {
+				break
+			}
+		}
+
+		if "" == record.Type {
+			break
+		}
+	}
+
+	return false
+}
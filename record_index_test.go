@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+const benchmarkZoneSize = 50000
+
+func benchmarkZone(size int) []libdns.Record {
+
+	var records = make([]libdns.Record, size)
+
+	for i := 0; i < size; i++ {
+		records[i] = libdns.TXT{Name: fmt.Sprintf("host-%d", i), Text: fmt.Sprintf("value-%d", i), TTL: 3600e9}
+	}
+
+	return records
+}
+
+func benchmarkInput(size, step int) []libdns.Record {
+
+	var records = make([]libdns.Record, 0, size)
+
+	for i := 0; i < size; i++ {
+		records = append(records, libdns.TXT{Name: fmt.Sprintf("host-%d", i*step), Text: "changed", TTL: 3600e9})
+	}
+
+	return records
+}
+
+func TestRecordIndex(t *testing.T) {
+
+	var index = NewRecordIndex([]libdns.Record{
+		libdns.TXT{Name: "a", Text: "x", TTL: 60e9},
+		libdns.TXT{Name: "A", Text: "y", TTL: 60e9},
+		libdns.RR{Name: "b", Type: "TXT"},
+		libdns.RR{Name: "c"},
+	})
+
+	var tests = []struct {
+		record libdns.RR
+		find   int
+		set    bool
+		covers bool
+	}{
+		{libdns.RR{Name: "a", Type: "TXT", Data: "x", TTL: 60e9}, 1, true, true},
+		{libdns.RR{Name: "a", Type: "TXT", Data: "x", TTL: 30e9}, 0, true, false},
+		{libdns.RR{Name: "a", Type: "TXT", Data: "z", TTL: 60e9}, 0, true, false},
+		{libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1"}, 0, false, false},
+		{libdns.RR{Name: "b", Type: "TXT", Data: "z", TTL: 60e9}, 0, true, true},
+		{libdns.RR{Name: "c", Type: "MX", Data: "10 mail", TTL: 60e9}, 0, false, true},
+	}
+
+	for _, test := range tests {
+		if x := len(index.Find(&test.record, true)); x != test.find {
+			t.Errorf("Find(%s) returned %d records, expected %d", FormatRecord(&test.record), x, test.find)
+		}
+
+		if x := index.HasSet(&test.record); x != test.set {
+			t.Errorf("HasSet(%s) returned %t, expected %t", FormatRecord(&test.record), x, test.set)
+		}
+
+		if x := index.Covers(&test.record); x != test.covers {
+			t.Errorf("Covers(%s) returned %t, expected %t", FormatRecord(&test.record), x, test.covers)
+		}
+	}
+
+	if x := len(index.Set(&libdns.RR{Name: "a", Type: "TXT"})); x != 2 {
+		t.Errorf("expected 2 records in set, got %d", x)
+	}
+}
+
+// BenchmarkMatchScan matches the input against the zone by scanning the
+// zone for every record, as the helpers did before the RecordIndex.
+func BenchmarkMatchScan(b *testing.B) {
+
+	var zone = benchmarkZone(benchmarkZoneSize)
+	var input = benchmarkInput(1000, 50)
+
+	for b.Loop() {
+		for _, record := range zone {
+			var rr = record.RR()
+			_ = IsInList(&rr, &input, true)
+		}
+	}
+}
+
+// BenchmarkMatchIndex matches the input against the zone with a RecordIndex
+func BenchmarkMatchIndex(b *testing.B) {
+
+	var zone = benchmarkZone(benchmarkZoneSize)
+	var input = benchmarkInput(1000, 50)
+
+	for b.Loop() {
+		var index = NewRecordIndex(input)
+
+		for _, record := range zone {
+			var rr = record.RR()
+			_ = index.Contains(&rr, true)
+		}
+	}
+}
+
+func BenchmarkPlanSet(b *testing.B) {
+
+	var zone = benchmarkZone(benchmarkZoneSize)
+	var input = benchmarkInput(1000, 50)
+
+	for b.Loop() {
+		if _, _, err := planSet(sliceStream(zone), input, DefaultMatcher, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPlanDelete(b *testing.B) {
+
+	var zone = benchmarkZone(benchmarkZoneSize)
+	var input = benchmarkInput(1000, 50)
+
+	for b.Loop() {
+		if _, _, err := planDelete(sliceStream(zone), input, DefaultMatcher, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"iter"
 	"sync"
 
 	"github.com/libdns/libdns"
@@ -9,12 +10,28 @@ import (
 
 // SetRecords updates existing records by marking them as either NoChange or Delete
 // based on the given input, and appends the input records with state Create.
+// When a removed and a created record share the same name and type, they are
+// paired as a single Update so clients can modify the record in place. The
+// returned list has the Interleaved order, so records are created before the
+// records they replace are removed where the record type allows it.
 // This ensures compliance with the libdns contract and produces the expected results.
 //
 // Example provided by the contract can be found here:
 // https://github.com/libdns/libdns/blob/master/libdns.go#L182-L216
 func SetRecords(ctx context.Context, mutex sync.Locker, client Client, zone string, records []libdns.Record) ([]libdns.Record, error) {
 
+	zone, err := normalizeZone(client, zone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records, err = normalizeRecords(client, zone, records)
+
+	if err != nil {
+		return nil, err
+	}
+
 	var unlock = lock(mutex)
 	var ret = make([]libdns.Record, 0)
 
@@ -22,71 +39,145 @@ func SetRecords(ctx context.Context, mutex sync.Locker, client Client, zone stri
 		defer unlock()
 	}
 
-	existing, err := GetRecords(ctx, nil, client, zone)
+	var filter = newRecordFilter(client, records)
+
+	change, _, err := planSet(fetchRecords(ctx, client, zone, filter), records, matcherOf(ctx, client, zone), nil != filter)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var change = NewChangeList(0, len(existing)+len(records))
-
-	for _, record := range RecordIterator(&existing) {
+	if false == change.Has(Delete|Create) {
+		return ret, nil
+	}
 
-		var state = NoChange
+	curr, err := client.SetDNSList(ctx, zone, change)
 
-		if found := lookupByNameAndType(&record, &records); found != nil {
+	if nil == err {
+		err = reportedFailure(change)
+	}
 
-			var rr = (*found).RR()
+	if err != nil {
+		return failure(ctx, client, zone, change, Create, err)
+	}
 
-			// only mark as delete when differs
-			if rr.Data != record.Data || rr.TTL != record.TTL {
-				state = Delete
-			}
-		}
+	if nil != unlock {
+		unlock()
+	}
 
-		change.addRecord(&record, state)
+	if unlock := rlock(mutex); nil != unlock {
+		defer unlock()
 	}
 
-	for _, item := range RecordIterator(&records) {
+	var matcher = matcherOf(ctx, client, zone)
+	var existing = NewRecordIndexWithMatcher(existingRecords(change), matcher)
+	var inputs = NewRecordIndexWithMatcher(records, matcher)
 
-		if IsInList(&item, &existing, true) {
-			continue
+	for item, err := range changedRecords(ctx, client, zone, curr, filter) {
+
+		if err != nil {
+			return nil, err
 		}
 
-		change.addRecord(&item, Create)
+		if record := item.RR(); false == existing.Contains(&record, true) && inputs.HasSet(&record) {
+			ret = append(ret, item)
+		}
 	}
 
-	if false == change.Has(Delete|Create) {
-		return ret, nil
-	}
+	return ret, nil
+}
 
-	curr, err := client.SetDNSList(ctx, zone, change)
+// PlanSet returns the ChangeList that SetRecords would pass to the client
+// together with the records that would be created, without applying it.
+func PlanSet(ctx context.Context, mutex sync.Locker, client Client, zone string, records []libdns.Record) (ChangeList, []libdns.Record, error) {
+
+	zone, err := normalizeZone(client, zone)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if nil != unlock {
-		unlock()
+	records, err = normalizeRecords(client, zone, records)
+
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if unlock := rlock(mutex); nil != unlock {
 		defer unlock()
 	}
 
-	if nil == curr {
-		curr, err = GetRecords(ctx, nil, client, zone)
+	var filter = newRecordFilter(client, records)
+
+	return planSet(fetchRecords(ctx, client, zone, filter), records, matcherOf(ctx, client, zone), nil != filter)
+}
+
+func planSet(existing iter.Seq2[libdns.Record, error], records []libdns.Record, matcher Matcher, partial bool) (ChangeList, []libdns.Record, error) {
+
+	var change = NewChangeList(0, len(records))
+	var inputs = NewRecordIndexWithMatcher(records, matcher)
+	var deletes = NewRecordIndexWithMatcher(nil, matcher)
+	var found = make([]bool, len(records))
+	var ret = make([]libdns.Record, 0)
+
+	for origin, err := range existing {
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+
+		var record = origin.RR()
+		var matches = inputs.Find(&record, true)
+
+		// keep track of the input records that already exist
+		for _, i := range matches {
+			found[i] = true
+		}
+
+		// only mark as delete when the set is part of the input
+		// and this record differs from all input records
+		if 0 == len(matches) && inputs.HasSet(&record) {
+			deletes.Add(origin)
+			continue
+		}
+
+		change.addRecord(origin, NoChange)
 	}
 
-	for x, record := range RecordIterator(&curr) {
-		if false == IsInList(&record, &existing, true) && nil != lookupByNameAndType(&record, &records) {
-			ret = append(ret, *x)
+	var paired = make([]bool, deletes.Len())
+
+	for i, c := 0, len(records); i < c; i++ {
+
+		if found[i] {
+			continue
+		}
+
+		ret = append(ret, records[i])
+
+		// pair with a removed record of the same set so it can
+		// be handled as an update by the client
+		if idx := pairUpdate(records[i], deletes, paired); idx >= 0 {
+			change.addUpdate(deletes.Record(idx), records[i])
+			paired[idx] = true
+			continue
 		}
+
+		change.addRecord(records[i], Create)
 	}
 
-	return ret, nil
+	for i, c := 0, deletes.Len(); i < c; i++ {
+		if false == paired[i] {
+			change.addRecord(deletes.Record(i), Delete)
+		}
+	}
+
+	if partial {
+		change.markPartial()
+	}
+
+	change.freeze()
+
+	// create new records before removing the old records where the
+	// record type allows it, so names keep resolving while replaced
+	return change.WithOrder(Interleaved), ret, nil
 }
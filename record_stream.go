@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// StreamingClient can be implemented by a Client for APIs that return the
+// records of a zone in pages. The helpers will then consume the records page
+// by page instead of waiting for the whole zone. Note that the ChangeList of
+// AppendRecords, SetRecords and DeleteRecords still holds every fetched record
+// (unchanged records as NoChange) because a client may need GetList, so only
+// StreamRecords iterates the zone without keeping the records in memory.
+type StreamingClient interface {
+	Client
+	// IterateDNSList returns an iterator over all records of the given zone,
+	// where the client handles the pagination of the API. An error should be
+	// yielded when fetching a page fails, which stops the iteration.
+	IterateDNSList(ctx context.Context, domain string) iter.Seq2[libdns.Record, error]
+}
+
+// StreamRecords returns an iterator over all records of the given zone, where
+// the records are parsed to their specific RR type. When the client implements
+// StreamingClient the records are fetched page by page, otherwise GetDNSList is
+// used. The iteration stops after the first error.
+func StreamRecords(ctx context.Context, mutex sync.Locker, client Client, zone string) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+
+		zone, err := normalizeZone(client, zone)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		if unlock := rlock(mutex); nil != unlock {
+			defer unlock()
+		}
+
+		for record, err := range streamRecords(ctx, client, zone) {
+			if false == yield(record, err) {
+				return
+			}
+		}
+	}
+}
+
+func streamRecords(ctx context.Context, client Client, zone string) iter.Seq2[libdns.Record, error] {
+
+	if v, ok := client.(StreamingClient); ok {
+		return relativeStream(client, zone, parseStream(v.IterateDNSList(ctx, zone)))
+	}
+
+	return func(yield func(libdns.Record, error) bool) {
+		list, err := client.GetDNSList(ctx, zone)
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for record, err := range relativeStream(client, zone, parseStream(sliceStream(list))) {
+			if false == yield(record, err) {
+				return
+			}
+		}
+	}
+}
+
+// changedRecords returns an iterator over the records returned by SetDNSList,
+// or over the records of the zone that match the filter when the client
+// returned nil.
+func changedRecords(ctx context.Context, client Client, zone string, records []libdns.Record, filter *recordFilter) iter.Seq2[libdns.Record, error] {
+
+	if nil == records {
+		return fetchRecords(ctx, client, zone, filter)
+	}
+
+	return relativeStream(client, zone, parseStream(sliceStream(records)))
+}
+
+func sliceStream(records []libdns.Record) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+		for _, record := range records {
+			if false == yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+func parseStream(records iter.Seq2[libdns.Record, error]) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+		for record, err := range records {
+
+			if nil == err {
+				record, err = parseRecord(record)
+			}
+
+			if false == yield(record, err) || nil != err {
+				return
+			}
+		}
+	}
+}
+
+// existingRecords returns the original records of the zone the change was
+// computed against.
+func existingRecords(change ChangeList) []libdns.Record {
+
+	var records = make([]libdns.Record, 0)
+
+	for _, record := range change.IterateOrigins(NoChange | Delete) {
+		records = append(records, record)
+	}
+
+	return records
+}
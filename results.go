@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+// RecordError describes a record of a ChangeList that the client failed
+// to apply.
+type RecordError struct {
+	Record *libdns.RR
+	State  ChangeState
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.State, FormatRecord(e.Record), e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// PartialError is returned when the client reported the results of the
+// individual records (see ChangeList.Report) and some of them failed. The
+// helpers will return this error together with the records that were
+// successfully applied.
+type PartialError struct {
+	// Err holds the error returned by SetDNSList, which can be nil when the
+	// client only reported the failures.
+	Err    error
+	Errors []*RecordError
+}
+
+func (e *PartialError) Error() string {
+
+	var messages = make([]string, len(e.Errors))
+
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+
+	var message = fmt.Sprintf("%d record(s) failed: %s", len(e.Errors), strings.Join(messages, "; "))
+
+	if nil != e.Err {
+		message = e.Err.Error() + ": " + message
+	}
+
+	return message
+}
+
+func (e *PartialError) Unwrap() []error {
+
+	var errs = make([]error, 0, len(e.Errors)+1)
+
+	if nil != e.Err {
+		errs = append(errs, e.Err)
+	}
+
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// changeResults holds the reported results of records, this is shared
+// between a list and the batches created from it.
+type changeResults struct {
+	mutex   sync.Mutex
+	results map[*libdns.RR]error
+}
+
+func newChangeResults() *changeResults {
+	return &changeResults{
+		results: make(map[*libdns.RR]error),
+	}
+}
+
+func (c *changes) Report(record *libdns.RR, err error) {
+	c.results.mutex.Lock()
+	defer c.results.mutex.Unlock()
+
+	c.results.results[record] = err
+}
+
+func (c *changes) Results() iter.Seq2[*libdns.RR, error] {
+	return func(yield func(*libdns.RR, error) bool) {
+		for _, record := range c.IterateStates(NoChange | Delete | Create) {
+			if err, ok := c.result(record); ok && false == yield(record, err) {
+				return
+			}
+		}
+	}
+}
+
+func (c *changes) result(record *libdns.RR) (error, bool) {
+	c.results.mutex.Lock()
+	defer c.results.mutex.Unlock()
+
+	err, ok := c.results.results[record]
+
+	return err, ok
+}
+
+func (c *changes) isReported() bool {
+	c.results.mutex.Lock()
+	defer c.results.mutex.Unlock()
+
+	return len(c.results.results) > 0
+}
+
+// failures returns a PartialError when the client reported failed records
+func (c *changes) failures(cause error) *PartialError {
+
+	var errs = make([]*RecordError, 0)
+
+	for state, record := range c.IterateStates(Delete | Create) {
+		if err, ok := c.result(record); ok && nil != err {
+			errs = append(errs, &RecordError{Record: record, State: state, Err: err})
+		}
+	}
+
+	if len(errs) == 0 && nil == cause {
+		return nil
+	}
+
+	return &PartialError{Err: cause, Errors: errs}
+}
+
+// applied returns the records of the given state that are reported as applied
+func (c *changes) applied(state ChangeState) ([]libdns.Record, error) {
+
+	var records = make([]libdns.Record, 0)
+
+	for record, origin := range c.IterateOrigins(state) {
+		if err, ok := c.result(record); ok && nil == err {
+			records = append(records, origin)
+		}
+	}
+
+	if err := parseRecords(records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// reportedFailure returns a PartialError when the client reported failed
+// records while SetDNSList did not return an error.
+func reportedFailure(change ChangeList) error {
+
+	if false == change.isReported() {
+		return nil
+	}
+
+	if err := change.failures(nil); nil != err {
+		return err
+	}
+
+	return nil
+}
+
+// failure handles the error of SetDNSList. When the client is RollbackAware
+// the zone is restored, otherwise when the client reported the results of
+// the records, the applied records of the given state are returned together
+// with a PartialError.
+func failure(ctx context.Context, client Client, zone string, change ChangeList, state ChangeState, cause error) ([]libdns.Record, error) {
+
+	if v, ok := client.(RollbackAware); (ok && v.RollbackOnFailure()) || false == change.isReported() {
+		return nil, rollback(ctx, client, zone, change, cause)
+	}
+
+	records, err := change.applied(state)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := cause.(*PartialError); ok {
+		return records, cause
+	}
+
+	return records, change.failures(cause)
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+// reportingClient is a memoryClient that reports the result of every record,
+// where the creates of the names in fail are reported as failed.
+type reportingClient struct {
+	memoryClient
+	err error
+}
+
+func (r *reportingClient) SetDNSList(ctx context.Context, domain string, change ChangeList) ([]libdns.Record, error) {
+
+	for record := range change.Iterate(Create) {
+
+		if slices.Contains(r.fail, record.Name) {
+			change.Report(record, errors.New("rejected"))
+			continue
+		}
+
+		r.records = append(r.records, *record)
+		change.Report(record, nil)
+	}
+
+	return nil, r.err
+}
+
+func TestSetRecordsPartialFailure(t *testing.T) {
+
+	for _, cause := range []error{nil, errors.New("request failed")} {
+
+		var client = &reportingClient{memoryClient: memoryClient{fail: []string{"b"}}, err: cause}
+
+		records, err := SetRecords(context.Background(), nil, client, "example.com.", []libdns.Record{
+			libdns.TXT{Name: "a", Text: "x"},
+			libdns.TXT{Name: "b", Text: "x"},
+		})
+
+		var partial *PartialError
+
+		if false == errors.As(err, &partial) {
+			t.Fatalf("expected a PartialError, got %v", err)
+		}
+
+		if partial.Err != cause {
+			t.Fatalf("expected the PartialError to hold %v, got %v", cause, partial.Err)
+		}
+
+		if 1 != len(partial.Errors) || "b" != partial.Errors[0].Record.Name || Create != partial.Errors[0].State {
+			t.Fatalf("expected a RecordError for the create of b, got %v", partial.Errors)
+		}
+
+		if 1 != len(records) || "a" != records[0].RR().Name {
+			t.Fatalf("expected the applied record a, got %v", records)
+		}
+	}
+}
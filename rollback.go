@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libdns/libdns"
+)
+
+// RollbackAware can be implemented by a Client to let the helpers restore
+// the zone when SetDNSList fails halfway. The zone will be fetched again to
+// determine which changes were applied, and the inverse of those changes
+// is passed to SetDNSList to restore the original records.
+type RollbackAware interface {
+	RollbackOnFailure() bool
+}
+
+// RollbackError is returned when SetDNSList failed and a rollback was
+// attempted. Err holds the original error and Rollback the error of the
+// rollback, which is nil when the zone was restored.
+type RollbackError struct {
+	Err      error
+	Rollback error
+	// Applied holds the changes that were applied before the failure
+	// and were reverted, this will be nil when the zone could not be
+	// fetched after the failure.
+	Applied ChangeList
+}
+
+func (e *RollbackError) Error() string {
+
+	if nil == e.Rollback {
+		return fmt.Sprintf("%s (changes rolled back)", e.Err)
+	}
+
+	return fmt.Sprintf("%s (rollback failed: %s)", e.Err, e.Rollback)
+}
+
+func (e *RollbackError) Unwrap() []error {
+
+	if nil == e.Rollback {
+		return []error{e.Err}
+	}
+
+	return []error{e.Err, e.Rollback}
+}
+
+// rollback will restore the zone after the given change failed when the
+// client is RollbackAware, otherwise the given error is returned as is.
+func rollback(ctx context.Context, client Client, zone string, change ChangeList, cause error) error {
+
+	if v, ok := client.(RollbackAware); !ok || false == v.RollbackOnFailure() {
+		return cause
+	}
+
+	curr, err := GetRecords(ctx, nil, client, zone)
+
+	if err != nil {
+		return &RollbackError{Err: cause, Rollback: err}
+	}
+
+	var applied = appliedChanges(change, curr, matcherOf(ctx, client, zone))
+
+	if applied.Has(Delete | Create) {
+		var inverse = applied.Invert()
+
+		if _, err := client.SetDNSList(ctx, zone, inverse); err != nil {
+			return &RollbackError{Err: cause, Rollback: err, Applied: applied}
+		}
+	}
+
+	return &RollbackError{Err: cause, Applied: applied}
+}
+
+// appliedChanges will return the changes that are found in the given records, where
+// the current records will be marked NoChange unless they were created by the change.
+// Records that already existed before the change are never marked as created, also
+// when they are part of the creates (like AppendRecords does for existing records).
+// The current records are used as origin, so provider data like ids of the created
+// records is available when the changes are reverted.
+func appliedChanges(change ChangeList, records []libdns.Record, matcher Matcher) ChangeList {
+
+	var current = NewRecordIndexWithMatcher(records, matcher)
+	var existing = NewRecordIndexWithMatcher(existingRecords(change), matcher)
+	var creates = NewRecordIndexWithMatcher(toRecords(change.Creates()), matcher)
+	var seen = make(map[int]int)
+	var updates = make(map[*libdns.RR]*libdns.RR)
+	var replaced = make(map[*libdns.RR]bool)
+	var applied = NewChangeList(0, len(records))
+
+	for previous, record := range change.IterateUpdates() {
+		if current.Contains(record, false) && false == current.Contains(previous, false) {
+			updates[record] = previous
+			replaced[previous] = true
+		}
+	}
+
+	for origin, record := range RecordIterator(&records) {
+
+		if updated := findUpdated(&record, updates, matcher); nil != updated {
+			applied.addUpdate(change.Origin(updates[updated]), *origin)
+			delete(updates, updated)
+			continue
+		}
+
+		var state = NoChange
+
+		// only the records beyond the number of records that
+		// existed before the change can be created by the change
+		if matches := creates.Find(&record, false); len(matches) > 0 {
+			seen[matches[0]]++
+
+			if n := seen[matches[0]] - len(existing.Find(&record, false)); n > 0 && n <= len(matches) {
+				state = Create
+			}
+		}
+
+		applied.addRecord(*origin, state)
+	}
+
+	for record := range change.Iterate(Delete) {
+		if false == replaced[record] && false == current.Contains(record, false) {
+			applied.addRecord(change.Origin(record), Delete)
+		}
+	}
+
+	applied.freeze()
+
+	return applied
+}
+
+func findUpdated(record *libdns.RR, updates map[*libdns.RR]*libdns.RR, matcher Matcher) *libdns.RR {
+	for x := range updates {
+		if IsSameRecord(matcher, x, record, false) {
+			return x
+		}
+	}
+	return nil
+}
+
+func toRecords(list []*libdns.RR) []libdns.Record {
+
+	var records = make([]libdns.Record, len(list))
+
+	for i, c := 0, len(list); i < c; i++ {
+		records[i] = *list[i]
+	}
+
+	return records
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestRollbackKeepsExistingRecords(t *testing.T) {
+
+	var client = &memoryClient{
+		records:  []libdns.Record{libdns.TXT{Name: "a", Text: "keep"}},
+		fail:     []string{"z"},
+		rollback: true,
+	}
+
+	_, err := AppendRecords(context.Background(), nil, client, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "a", Text: "keep"},
+		libdns.TXT{Name: "z", Text: "fail"},
+	})
+
+	var rollbackErr *RollbackError
+
+	if false == errors.As(err, &rollbackErr) || nil != rollbackErr.Rollback {
+		t.Fatalf("expected a successful rollback, got %v", err)
+	}
+
+	if len(client.records) != 1 || client.records[0].RR().Data != "keep" {
+		t.Fatalf("expected the existing record to be kept, got %v", client.records)
+	}
+}
+
+func TestRollbackRemovesCreatedDuplicates(t *testing.T) {
+
+	var client = &memoryClient{
+		records:  []libdns.Record{libdns.TXT{Name: "a", Text: "keep"}},
+		fail:     []string{"z"},
+		rollback: true,
+	}
+
+	var change = NewChangeListBuilder().
+		Add(NoChange, libdns.TXT{Name: "a", Text: "keep"}).
+		Add(Create, libdns.TXT{Name: "a", Text: "keep"}, libdns.TXT{Name: "z", Text: "fail"}).
+		Build()
+
+	_, err := client.SetDNSList(context.Background(), "example.com.", change)
+
+	if err = rollback(context.Background(), client, "example.com.", change, err); nil == err {
+		t.Fatal("expected an error")
+	}
+
+	if len(client.records) != 1 {
+		t.Fatalf("expected only the existing record, got %v", client.records)
+	}
+}
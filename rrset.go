@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+type RRSetAction uint8
+
+const (
+	// RRSetCreate means that the set does not exist yet and will be created
+	RRSetCreate RRSetAction = iota + 1
+	// RRSetReplace means that the existing set will be replaced with the records of After
+	RRSetReplace
+	// RRSetDelete means that the whole set will be removed
+	RRSetDelete
+)
+
+func (a RRSetAction) String() string {
+	switch a {
+	case RRSetCreate:
+		return "create"
+	case RRSetReplace:
+		return "replace"
+	case RRSetDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// RRSet represents all records with the same name and type, which is how
+// many DNS APIs (Route53, PowerDNS, Google Cloud DNS...) manage records.
+type RRSet struct {
+	Name string
+	Type string
+	// Before holds the records of the set as they currently exist
+	Before []*libdns.RR
+	// After holds the records of the set as they should exist
+	After  []*libdns.RR
+	Action RRSetAction
+}
+
+// changeSet holds the changes of records with the same name and type
+type changeSet struct {
+	name    string
+	kind    string
+	records []*ChangeRecord
+}
+
+func (s *changeSet) has(state ChangeState) bool {
+	return s.count(state) > 0
+}
+
+func (s *changeSet) count(state ChangeState) int {
+	var count = 0
+	for _, record := range s.records {
+		if 0 != (record.state & state) {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *changeSet) rrset() *RRSet {
+
+	var set = &RRSet{
+		Name:   s.name,
+		Type:   s.kind,
+		Before: make([]*libdns.RR, 0),
+		After:  make([]*libdns.RR, 0),
+	}
+
+	for _, record := range s.records {
+		switch record.state {
+		case NoChange:
+			set.Before = append(set.Before, record.record)
+			set.After = append(set.After, record.record)
+		case Delete:
+			set.Before = append(set.Before, record.record)
+		case Create:
+			set.After = append(set.After, record.record)
+		case Update:
+			set.Before = append(set.Before, record.previous)
+			set.After = append(set.After, record.record)
+		}
+	}
+
+	switch {
+	case len(set.Before) == 0:
+		set.Action = RRSetCreate
+	case len(set.After) == 0:
+		set.Action = RRSetDelete
+	default:
+		set.Action = RRSetReplace
+	}
+
+	return set
+}
+
+// sets groups the records by name and type in the order they are
+// first seen in the list.
+func (c *changes) sets() []*changeSet {
+
+	var sets = make([]*changeSet, 0)
+	var index = make(map[string]*changeSet)
+
+	for _, record := range c.records {
+
+		if nil == record {
+			continue
+		}
+
+		var key = strings.ToLower(record.record.Name) + "\x00" + record.record.Type
+
+		if _, ok := index[key]; !ok {
+			index[key] = &changeSet{name: record.record.Name, kind: record.record.Type}
+			sets = append(sets, index[key])
+		}
+
+		index[key].records = append(index[key].records, record)
+	}
+
+	return sets
+}
+
+func (c *changes) RRSets() iter.Seq[*RRSet] {
+	return func(yield func(*RRSet) bool) {
+		for _, set := range c.sets() {
+			if set.has(Delete|Create|Update) && false == yield(set.rrset()) {
+				return
+			}
+		}
+	}
+}
+
+// setWeight is used to order the sets so that sets that only remove
+// records go first and sets that only add records last.
+func setWeight(set *changeSet) int {
+	switch {
+	case false == set.has(Create|Update):
+		return 0
+	case false == set.has(Delete|Update|NoChange):
+		return 2
+	default:
+		return 1
+	}
+}
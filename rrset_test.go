@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestRRSets(t *testing.T) {
+
+	var tests = []struct {
+		name    string
+		records map[ChangeState][]libdns.RR
+		action  RRSetAction
+		before  int
+		after   int
+	}{
+		{
+			"create",
+			map[ChangeState][]libdns.RR{
+				Create: {{Name: "a", Type: "TXT", Data: "x"}, {Name: "a", Type: "TXT", Data: "y"}},
+			},
+			RRSetCreate, 0, 2,
+		},
+		{
+			"replace",
+			map[ChangeState][]libdns.RR{
+				NoChange: {{Name: "a", Type: "TXT", Data: "x"}},
+				Delete:   {{Name: "a", Type: "TXT", Data: "y"}},
+				Create:   {{Name: "a", Type: "TXT", Data: "z"}},
+			},
+			RRSetReplace, 2, 2,
+		},
+		{
+			"delete",
+			map[ChangeState][]libdns.RR{
+				Delete: {{Name: "a", Type: "TXT", Data: "x"}, {Name: "a", Type: "TXT", Data: "y"}},
+			},
+			RRSetDelete, 2, 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var change = NewChangeList()
+
+			// a set without changes that should be left out
+			change.addRecord(libdns.RR{Name: "b", Type: "TXT", Data: "x"}, NoChange)
+
+			for _, state := range []ChangeState{NoChange, Delete, Create} {
+				for _, record := range test.records[state] {
+					change.addRecord(record, state)
+				}
+			}
+
+			change.freeze()
+
+			var sets = make([]*RRSet, 0)
+
+			for set := range change.RRSets() {
+				sets = append(sets, set)
+			}
+
+			if 1 != len(sets) {
+				t.Fatalf("expected 1 set, got %d", len(sets))
+			}
+
+			if set := sets[0]; set.Action != test.action || len(set.Before) != test.before || len(set.After) != test.after || "a" != set.Name || "TXT" != set.Type {
+				t.Fatalf("unexpected set %s %s %s with %d before and %d after", set.Name, set.Type, set.Action, len(set.Before), len(set.After))
+			}
+		})
+	}
+}
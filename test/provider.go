@@ -88,38 +88,33 @@ func RunProviderTests(t *testing.T, provider Provider, mode TestMode) {
 
 func printRecords(t *testing.T, records []libdns.Record, invalid libdns.Record, prefix string) {
 
-	var buf = new(bytes.Buffer)
-	var writer = tabwriter.NewWriter(buf, 0, 4, 2, ' ', tabwriter.Debug)
+	var list = make([]*libdns.RR, 0, len(records)+1)
+	var prefixes = make([]string, 0, len(records)+1)
 	var isWritten = false
-	var write = func(prefix string, record libdns.RR) {
-		_, _ = fmt.Fprintf(writer, "%s%s\t %s\t %s\t %s\n", prefix, record.Name, record.TTL, record.Type, record.Data)
-	}
 
-	for _, record := range records {
-		var rr = record.RR()
+	for _, record := range helper.RecordIterator(&records) {
 
 		if invalid != nil {
 			prefix = "✓ "
 
-			if record.RR().Type == invalid.RR().Type && record.RR().Data == invalid.RR().Data && strings.EqualFold(record.RR().Name, invalid.RR().Name) {
+			if record.Type == invalid.RR().Type && record.Data == invalid.RR().Data && strings.EqualFold(record.Name, invalid.RR().Name) {
 				prefix = "× "
 				isWritten = true
 			}
 		}
 
-		write(prefix, rr)
+		list = append(list, &record)
+		prefixes = append(prefixes, prefix)
 	}
 
 	if false == isWritten && nil != invalid {
-		write("× ", invalid.RR())
+		var rr = invalid.RR()
+		list = append(list, &rr)
+		prefixes = append(prefixes, "× ")
 	}
 
-	_ = writer.Flush()
-
-	scanner := bufio.NewScanner(buf)
-
-	for scanner.Scan() {
-		t.Log(scanner.Text())
+	for i, line := range helper.FormatRecords(list) {
+		t.Log(prefixes[i] + line)
 	}
 }
 
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidZone is used when the name of a zone is empty or malformed
+var ErrInvalidZone = errors.New("invalid zone")
+
+// ZoneError describes a zone name that is empty or malformed
+type ZoneError struct {
+	Zone   string
+	Reason string
+}
+
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("invalid zone \"%s\": %s", e.Zone, e.Reason)
+}
+
+func (e *ZoneError) Unwrap() error {
+	return ErrInvalidZone
+}
+
+// validateZone returns a ZoneError when the zone is empty or malformed
+func validateZone(zone string) error {
+
+	var name = strings.TrimSuffix(zone, ".")
+
+	if "" == name {
+		return &ZoneError{Zone: zone, Reason: "empty name"}
+	}
+
+	if len(name) > 253 {
+		return &ZoneError{Zone: zone, Reason: "name exceeds 253 characters"}
+	}
+
+	for _, label := range strings.Split(name, ".") {
+
+		if "" == label {
+			return &ZoneError{Zone: zone, Reason: "empty label"}
+		}
+
+		if len(label) > 63 {
+			return &ZoneError{Zone: zone, Reason: fmt.Sprintf("label \"%s\" exceeds 63 characters", label)}
+		}
+
+		if strings.ContainsFunc(label, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+			return &ZoneError{Zone: zone, Reason: fmt.Sprintf("label \"%s\" contains whitespace", label)}
+		}
+	}
+
+	return nil
+}
+
+// asciiZone validates the zone and returns it with A-labels
+func asciiZone(zone string) (string, error) {
+
+	name, err := ToASCII(zone)
+
+	if err != nil {
+		return "", &ZoneError{Zone: zone, Reason: err.Error()}
+	}
+
+	if err := validateZone(name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// zoneName validates the zone and returns it with the labels in the form
+// expected by the client, see ClientCapabilities.UnicodeNames.
+func zoneName(client any, zone string) (string, error) {
+
+	name, err := asciiZone(zone)
+
+	if err != nil || false == unicodeNames(client) {
+		return name, err
+	}
+
+	if name, err = ToUnicode(name); err != nil {
+		return "", &ZoneError{Zone: zone, Reason: err.Error()}
+	}
+
+	return name, nil
+}
+
+// normalizeZone validates the zone and returns it in the form expected by
+// the client, which is lowercase with a trailing dot and A-labels unless
+// configured otherwise with the ClientCapabilities.
+func normalizeZone(client any, zone string) (string, error) {
+
+	name, err := zoneName(client, zone)
+
+	if err != nil {
+		return "", err
+	}
+
+	var capabilities = &ClientCapabilities{}
+
+	if v, ok := client.(CapabilitiesAware); ok && nil != v.Capabilities() {
+		capabilities = v.Capabilities()
+	}
+
+	if false == capabilities.CaseSensitiveZones {
+		name = strings.ToLower(name)
+	}
+
+	name = strings.TrimSuffix(name, ".")
+
+	if false == capabilities.OmitZoneTrailingDot {
+		name += "."
+	}
+
+	return name, nil
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// FindZone returns the managed zone that holds the given FQDN, together with
+// the name of the record relative to that zone, where internationalized
+// names are matched and returned as A-labels. When multiple zones match,
+// the longest (most specific) zone is returned. For example, with the zones
+// "example.co.uk." and "b.example.co.uk." the FQDN
+// "_acme-challenge.a.b.example.co.uk." returns the zone "b.example.co.uk."
+// and the name "_acme-challenge.a".
+//
+// Pass a ZoneResolver as client to use a cached zone list. When no managed
+// zone covers the name, an error wrapping ErrZoneNotFound is returned.
+func FindZone(ctx context.Context, client ZoneAwareClient, fqdn string) (libdns.Zone, string, error) {
+
+	domains, err := client.Domains(ctx)
+
+	if err != nil {
+		return libdns.Zone{}, "", err
+	}
+
+	name, err := ToASCII(strings.TrimSuffix(fqdn, "."))
+
+	if err != nil {
+		return libdns.Zone{}, "", err
+	}
+
+	var key = strings.ToLower(name)
+	var match string
+
+	for _, domain := range domains {
+		var curr = normalizeZoneKey(domain.Name())
+
+		if "" == curr || len(curr) <= len(match) {
+			continue
+		}
+
+		if key == curr || strings.HasSuffix(key, "."+curr) {
+			match = curr
+		}
+	}
+
+	if "" == match {
+		return libdns.Zone{}, "", fmt.Errorf("%w: no managed zone for \"%s\"", ErrZoneNotFound, fqdn)
+	}
+
+	var relative = "@"
+
+	if len(name) > len(match) {
+		relative = name[:len(name)-len(match)-1]
+	}
+
+	return libdns.Zone{Name: match + "."}, relative, nil
+}
+
+// FindZone returns the managed zone of the given FQDN using the cached
+// domains, see FindZone.
+func (z *ZoneResolver) FindZone(ctx context.Context, fqdn string) (libdns.Zone, string, error) {
+	return FindZone(ctx, z, fqdn)
+}
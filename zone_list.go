@@ -11,7 +11,9 @@ import (
 // domains, which can be used as zones.
 //
 // This function ensures that the returned domain names include a trailing dot
-// to indicate the root zone.
+// to indicate the root zone, and that internationalized names are returned as
+// A-labels. An error wrapping ErrInvalidZone is returned for empty or malformed
+// domain names.
 func ListZones(ctx context.Context, mutex sync.Locker, client ZoneAwareClient) ([]libdns.Zone, error) {
 
 	if unlock := lock(mutex); nil != unlock {
@@ -28,7 +30,11 @@ func ListZones(ctx context.Context, mutex sync.Locker, client ZoneAwareClient) (
 
 	for i, c := 0, len(domains); i < c; i++ {
 
-		var name = domains[i].Name()
+		name, err := asciiZone(domains[i].Name())
+
+		if err != nil {
+			return nil, err
+		}
 
 		if name[len(name)-1] != '.' {
 			name += "."
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrZoneNotFound is returned when a zone is not managed by the client
+var ErrZoneNotFound = errors.New("zone not found")
+
+// DomainID can be implemented by a Domain for APIs that address zones by
+// an identifier instead of the name.
+type DomainID interface {
+	ID() string
+}
+
+// ZoneResolver wraps a ZoneAwareClient and caches the domains returned by
+// Domains, so the name of a zone can be resolved to the identifier used by
+// the API without fetching the domains on every call. The optional
+// interfaces of the client (like CapabilitiesAware) are forwarded.
+//
+// Because it implements ZoneAwareClient itself, it can also be used with
+// ListZones. A client can hold a resolver to resolve the zone ids in its
+// GetDNSList and SetDNSList implementation:
+//
+//	func (c *client) GetDNSList(ctx context.Context, domain string) ([]libdns.Record, error) {
+//		id, err := c.resolver.Resolve(ctx, domain)
+//		// ...
+//	}
+type ZoneResolver struct {
+	ZoneAwareClient
+	forwarder
+	ttl     time.Duration
+	mutex   sync.Mutex
+	domains []Domain
+	index   map[string]Domain
+	expires time.Time
+	forced  time.Time
+}
+
+// NewZoneResolver creates a resolver that caches the domains for the given
+// ttl, a ttl of zero will cache the domains until Reset is called.
+func NewZoneResolver(client ZoneAwareClient, ttl time.Duration) *ZoneResolver {
+	return &ZoneResolver{
+		ZoneAwareClient: client,
+		forwarder:       forwarder{client: client},
+		ttl:             ttl,
+	}
+}
+
+// Domains returns the cached domains and fetches them from the client when
+// the cache is expired.
+func (z *ZoneResolver) Domains(ctx context.Context) ([]Domain, error) {
+	z.mutex.Lock()
+	defer z.mutex.Unlock()
+
+	if err := z.load(ctx, false); err != nil {
+		return nil, err
+	}
+
+	return z.domains, nil
+}
+
+// Resolve returns the identifier of the given zone, or the name of the
+// domain when it does not implement DomainID. The zone is matched case
+// insensitive and with or without trailing dot. When the zone is not
+// found in the cache the domains are fetched again before returning
+// ErrZoneNotFound, which is done at most once per ttl (or once until
+// Reset is called for a ttl of zero) so unknown zones will not reload
+// the domains on every call.
+func (z *ZoneResolver) Resolve(ctx context.Context, zone string) (string, error) {
+	z.mutex.Lock()
+	defer z.mutex.Unlock()
+
+	if err := z.load(ctx, false); err != nil {
+		return "", err
+	}
+
+	domain, ok := z.index[normalizeZoneKey(zone)]
+
+	if !ok && z.canForce() {
+		z.forced = time.Now()
+
+		if err := z.load(ctx, true); err != nil {
+			return "", err
+		}
+
+		domain, ok = z.index[normalizeZoneKey(zone)]
+	}
+
+	if !ok {
+		return "", fmt.Errorf("%w: \"%s\"", ErrZoneNotFound, zone)
+	}
+
+	if v, ok := domain.(DomainID); ok {
+		return v.ID(), nil
+	}
+
+	return domain.Name(), nil
+}
+
+// Reset clears the cache
+func (z *ZoneResolver) Reset() {
+	z.mutex.Lock()
+	defer z.mutex.Unlock()
+
+	z.domains, z.index, z.forced = nil, nil, time.Time{}
+}
+
+// canForce reports whether the domains can be reloaded for an unknown zone
+func (z *ZoneResolver) canForce() bool {
+
+	if z.forced.IsZero() {
+		return true
+	}
+
+	return z.ttl > 0 && time.Since(z.forced) >= z.ttl
+}
+
+func (z *ZoneResolver) load(ctx context.Context, force bool) error {
+
+	if false == force && nil != z.index && (z.ttl == 0 || time.Now().Before(z.expires)) {
+		return nil
+	}
+
+	domains, err := z.ZoneAwareClient.Domains(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	z.domains = domains
+	z.index = make(map[string]Domain, len(domains))
+	z.expires = time.Now().Add(z.ttl)
+
+	for _, domain := range domains {
+		z.index[normalizeZoneKey(domain.Name())] = domain
+	}
+
+	return nil
+}
+
+func normalizeZoneKey(zone string) string {
+
+	if ascii, err := ToASCII(zone); nil == err {
+		zone = ascii
+	}
+
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingClient is a zoneClient that counts the calls to Domains
+type countingClient struct {
+	zoneClient
+	calls int
+}
+
+func (c *countingClient) Domains(ctx context.Context) ([]Domain, error) {
+	c.calls++
+	return c.zoneClient.Domains(ctx)
+}
+
+func TestZoneResolverLimitsReloads(t *testing.T) {
+
+	var client = &countingClient{zoneClient: zoneClient{domains: []string{"example.com"}}}
+	var resolver = NewZoneResolver(client, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.Resolve(context.Background(), "example.org."); false == errors.Is(err, ErrZoneNotFound) {
+			t.Fatalf("expected ErrZoneNotFound, got %v", err)
+		}
+	}
+
+	if 2 != client.calls {
+		t.Fatalf("expected the domains to be loaded and reloaded once, got %d calls", client.calls)
+	}
+
+	resolver.Reset()
+
+	client.domains = append(client.domains, "example.org")
+
+	if id, err := resolver.Resolve(context.Background(), "example.org."); err != nil || "example.org" != id {
+		t.Fatalf("expected example.org after reset, got \"%s\" (%v)", id, err)
+	}
+}
+
+func TestZoneResolverForwardsCapabilities(t *testing.T) {
+
+	var resolver Client = NewZoneResolver(&zoneClient{unicode: true}, time.Hour)
+
+	if false == unicodeNames(resolver) {
+		t.Fatal("expected the capabilities of the client to be forwarded")
+	}
+}
+
+func TestZoneResolverNotFound(t *testing.T) {
+
+	_, err := NewZoneResolver(&zoneClient{domains: []string{"example.com"}}, time.Hour).Resolve(context.Background(), "example.org.")
+
+	if false == errors.Is(err, ErrZoneNotFound) || `zone not found: "example.org."` != err.Error() {
+		t.Fatalf("expected ErrZoneNotFound with the zone name, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeZone(t *testing.T) {
+
+	var tests = []struct {
+		zone         string
+		capabilities *ClientCapabilities
+		expected     string
+		err          bool
+	}{
+		{"example.com", nil, "example.com.", false},
+		{"Example.COM.", nil, "example.com.", false},
+		{"Example.COM.", &ClientCapabilities{CaseSensitiveZones: true}, "Example.COM.", false},
+		{"example.com.", &ClientCapabilities{OmitZoneTrailingDot: true}, "example.com", false},
+		{"Bücher.example", nil, "xn--bcher-kva.example.", false},
+		{"xn--bcher-kva.example", &ClientCapabilities{UnicodeNames: true}, "bücher.example.", false},
+		{"", nil, "", true},
+		{".", nil, "", true},
+		{"example..com", nil, "", true},
+		{strings.Repeat("a", 64) + ".com", nil, "", true},
+		{strings.Repeat("a.", 127) + "com", nil, "", true},
+		{"exa mple.com", nil, "", true},
+	}
+
+	for _, test := range tests {
+		zone, err := normalizeZone(&capabilitiesClient{capabilities: test.capabilities}, test.zone)
+
+		if test.err {
+			var x *ZoneError
+
+			if false == errors.Is(err, ErrInvalidZone) || false == errors.As(err, &x) {
+				t.Errorf("normalizeZone(%q) expected a ZoneError, got %v", test.zone, err)
+			}
+			continue
+		}
+
+		if err != nil || zone != test.expected {
+			t.Errorf("normalizeZone(%q) = %q, %v, expected %q", test.zone, zone, err, test.expected)
+		}
+	}
+}